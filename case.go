@@ -0,0 +1,103 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// This file has helpers for working with the test case structs which make up
+// a table. These are defined by the user, so we know nothing about them except
+// that some of their fields will be Expects.
+//
+// Test case structs are almost always anonymous structs with unexported fields,
+// which reflect won't let us read or write by default. Since the structs are
+// the user's own test configuration (and not, say, a third party's internals),
+// we bypass this restriction.
+
+var (
+	expectType      = reflect.TypeOf(Expect{})
+	expectSliceType = reflect.TypeOf([]Expect(nil))
+	typedExpectType = reflect.TypeOf((*typedExpect)(nil)).Elem()
+)
+
+// caseValue returns an addressable copy of the given test case, which must be
+// a struct.
+func caseValue(tc any) reflect.Value {
+	v := reflect.ValueOf(tc)
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tpp: test case must be a struct, got %T", tc))
+	}
+
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+// caseField returns the i'th field of the addressable struct v, such that it
+// can be both read and written regardless of whether it is exported.
+func caseField(v reflect.Value, i int) reflect.Value {
+	f := v.Field(i)
+	if f.CanSet() {
+		return f
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// caseNameField returns the index of the field which holds the test case's
+// name, or -1 if there isn't one. By convention, this is a string field called
+// "name" or "Name".
+func caseNameField(typ reflect.Type) int {
+	for _, name := range []string{"name", "Name"} {
+		f, ok := typ.FieldByName(name)
+		if ok && len(f.Index) == 1 && f.Type.Kind() == reflect.String {
+			return f.Index[0]
+		}
+	}
+	return -1
+}
+
+// caseExpect is an Expect, []Expect or typed Expect field of a test case.
+type caseExpect struct {
+	field   string
+	expects []Expect
+	zero    bool
+	desc    string
+}
+
+// caseExpects returns the Expect, []Expect and typed Expect fields of the test
+// case struct v, in the order they're declared, with their descriptions.
+//
+// Zero-valued fields are included, and described by what Expectorise will do
+// with them: a zero Expect, or a nil []Expect, is Maybe.
+func caseExpects(v reflect.Value) []caseExpect {
+	var ces []caseExpect
+	for i := 0; i < v.NumField(); i++ {
+		ce := caseExpect{field: v.Type().Field(i).Name}
+
+		switch f := caseField(v, i); f.Type() {
+		case expectType:
+			e := f.Interface().(Expect)
+			ce.expects, ce.zero, ce.desc = []Expect{e}, f.IsZero(), e.describe()
+
+		case expectSliceType:
+			ee := f.Interface().([]Expect)
+			ce.expects, ce.zero = ee, f.IsNil()
+			if ce.zero {
+				ce.desc = Expect{}.describe()
+			} else {
+				ce.desc = describeMulti(ee)
+			}
+
+		default:
+			if !f.Type().Implements(typedExpectType) {
+				continue
+			}
+			e := f.Interface().(typedExpect).Expect()
+			ce.expects, ce.zero, ce.desc = []Expect{e}, f.IsZero(), e.describe()
+		}
+
+		ces = append(ces, ce)
+	}
+	return ces
+}
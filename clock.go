@@ -0,0 +1,245 @@
+package tpp
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is a fake clock, for subjects which take a clock rather than using the
+// time package directly. Time only moves when it's advanced, either directly
+// by Advance, or by a mock call with AdvanceBy. This lets tables test retry
+// backoff, TTLs and the like without real sleeps. For example:
+//
+//	getFoo: []tpp.Expect{
+//		tpp.Err().Then(tpp.AdvanceBy(time.Second)),
+//		tpp.OK("foo"),
+//	},
+//
+//	...
+//
+//	clock := tpp.NewClock(time.Time{})
+//	tpp.ExpectoriseMulti(tt.getFoo, func() tpp.MockCall {
+//		return mock.EXPECT().GetFoo()
+//	}, tpp.WithClock(clock))
+//
+// AdvanceBy happens during the mock call, so it's for modelling slow
+// dependencies: it can't fire a timer which the subject only starts after the
+// call returns. Subjects which wait should use Sleep, which advances the clock
+// itself, or the meta-test should call Advance.
+//
+// It's safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+// NewClock returns a Clock set to the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel which receives the time once the clock has advanced
+// by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C
+}
+
+// Sleep advances the clock by d. There's nothing else to wait for, so it
+// returns straight away. Like time.Sleep, it does nothing if d isn't positive.
+//
+// Note that this advances the clock for everyone, so it's best avoided if more
+// than one goroutine sleeps at once.
+func (c *Clock) Sleep(d time.Duration) {
+	if d > 0 {
+		c.Advance(d)
+	}
+}
+
+// NewTimer returns a ClockTimer which fires once the clock has advanced by d.
+func (c *Clock) NewTimer(d time.Duration) *ClockTimer {
+	ch := make(chan time.Time, 1)
+	t := &ClockTimer{C: ch, w: &clockWaiter{clock: c, ch: ch}}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker returns a ClockTicker which fires each time the clock advances by
+// another d. It panics if d isn't positive, like time.NewTicker.
+func (c *Clock) NewTicker(d time.Duration) *ClockTicker {
+	if d <= 0 {
+		panic("tpp: non-positive interval for Clock.NewTicker")
+	}
+
+	ch := make(chan time.Time, 1)
+	t := &ClockTicker{C: ch, w: &clockWaiter{clock: c, ch: ch}}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by d, firing any timers and tickers which
+// are due, in order. It panics if d is negative, since the clock can't go
+// back.
+func (c *Clock) Advance(d time.Duration) {
+	if d < 0 {
+		panic("tpp: negative duration for Clock.Advance")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for len(c.waiters) > 0 && !c.waiters[0].at.After(end) {
+		w := c.waiters[0]
+		c.now = w.at
+		w.fire()
+
+		c.waiters = c.waiters[1:]
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+			c.schedule(w)
+		}
+	}
+	c.now = end
+}
+
+// schedule adds w to the waiters, in order of when they're due. It must be
+// called with c.mu held.
+func (c *Clock) schedule(w *clockWaiter) {
+	i := sort.Search(len(c.waiters), func(i int) bool {
+		return c.waiters[i].at.After(w.at)
+	})
+	c.waiters = append(c.waiters, nil)
+	copy(c.waiters[i+1:], c.waiters[i:])
+	c.waiters[i] = w
+}
+
+// unschedule removes w from the waiters, returning whether it was there. It
+// must be called with c.mu held.
+func (c *Clock) unschedule(w *clockWaiter) bool {
+	for i, o := range c.waiters {
+		if o == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ClockTimer is a timer for a Clock, like time.Timer.
+type ClockTimer struct {
+	C <-chan time.Time
+	w *clockWaiter
+}
+
+// Stop stops the timer from firing, returning whether it was active.
+func (t *ClockTimer) Stop() bool {
+	return t.w.stop()
+}
+
+// Reset changes the timer to fire once the clock has advanced by d from now,
+// returning whether it was active.
+func (t *ClockTimer) Reset(d time.Duration) bool {
+	return t.w.reset(d, 0)
+}
+
+// ClockTicker is a ticker for a Clock, like time.Ticker.
+type ClockTicker struct {
+	C <-chan time.Time
+	w *clockWaiter
+}
+
+// Stop stops the ticker.
+func (t *ClockTicker) Stop() {
+	t.w.stop()
+}
+
+// Reset stops the ticker and resets its period to d. It panics if d isn't
+// positive, like time.Ticker.Reset.
+func (t *ClockTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("tpp: non-positive interval for ClockTicker.Reset")
+	}
+	t.w.reset(d, d)
+}
+
+// clockWaiter is a timer or ticker waiting for the clock to reach at. Tickers
+// have a period, and are rescheduled each time they fire.
+type clockWaiter struct {
+	clock  *Clock
+	ch     chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+// fire sends the time on the channel, unless there's already one waiting to be
+// received. Like time.Ticker, slow receivers miss ticks.
+func (w *clockWaiter) fire() {
+	select {
+	case w.ch <- w.at:
+	default:
+	}
+}
+
+func (w *clockWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.unschedule(w)
+}
+
+func (w *clockWaiter) reset(d, period time.Duration) bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.unschedule(w)
+	w.at = c.now.Add(d)
+	w.period = period
+
+	if d <= 0 {
+		w.fire()
+		return active
+	}
+
+	c.schedule(w)
+	return active
+}
+
+// -----------------------------------------------------------------------------
+// Effects ---------------------------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// AdvanceBy returns an Effect which advances the clock by d when the mock is
+// called. The clock is given to Expectorise with WithClock. d mustn't be
+// negative.
+func AdvanceBy(d time.Duration) Effect {
+	return Effect{
+		desc: fmt.Sprintf("AdvanceBy(%s)", d),
+		bind: func(opts *expectoriseOptions) (func(), error) {
+			if opts.clock == nil {
+				return nil, fmt.Errorf("tpp.AdvanceBy() needs a clock: pass tpp.WithClock() to Expectorise")
+			}
+			if d < 0 {
+				return nil, fmt.Errorf("tpp.AdvanceBy() can't move the clock back by %s", -d)
+			}
+			clock := opts.clock
+			return func() { clock.Advance(d) }, nil
+		},
+	}
+}
+
+// WithClock sets the clock used by Effects such as AdvanceBy.
+func WithClock(c *Clock) ExpectoriseOption {
+	return func(opt *expectoriseOptions) {
+		opt.clock = c
+	}
+}
@@ -0,0 +1,195 @@
+package tpp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestClock(t *testing.T) {
+	t.Run("Now only moves when advanced", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		require.Equal(t, epoch, c.Now())
+
+		c.Advance(time.Minute)
+		require.Equal(t, epoch.Add(time.Minute), c.Now())
+
+		c.Sleep(time.Second)
+		require.Equal(t, epoch.Add(time.Minute+time.Second), c.Now())
+	})
+
+	t.Run("After fires once due", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		ch := c.After(time.Second)
+
+		c.Advance(999 * time.Millisecond)
+		requireNotFired(t, ch)
+
+		c.Advance(time.Millisecond)
+		require.Equal(t, epoch.Add(time.Second), <-ch)
+	})
+
+	t.Run("timers fire in order", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		late, early := c.NewTimer(2*time.Second), c.NewTimer(time.Second)
+
+		var fired []time.Time
+		c.Advance(time.Hour)
+		fired = append(fired, <-early.C, <-late.C)
+
+		require.Equal(t, []time.Time{epoch.Add(time.Second), epoch.Add(2 * time.Second)}, fired)
+	})
+
+	t.Run("Stop and Reset timer", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		timer := c.NewTimer(time.Second)
+
+		require.True(t, timer.Stop())
+		require.False(t, timer.Stop())
+		c.Advance(time.Hour)
+		requireNotFired(t, timer.C)
+
+		require.False(t, timer.Reset(time.Second))
+		c.Advance(time.Second)
+		require.Equal(t, epoch.Add(time.Hour+time.Second), <-timer.C)
+	})
+
+	t.Run("ticker ticks and drops missed ticks", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		c.Advance(time.Second)
+		require.Equal(t, epoch.Add(time.Second), <-ticker.C)
+
+		c.Advance(3 * time.Second)
+		require.Equal(t, epoch.Add(2*time.Second), <-ticker.C)
+		requireNotFired(t, ticker.C)
+
+		ticker.Stop()
+		c.Advance(time.Hour)
+		requireNotFired(t, ticker.C)
+	})
+
+	t.Run("panics on invalid durations", func(t *testing.T) {
+		c := tpp.NewClock(epoch)
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		require.PanicsWithValue(t, "tpp: non-positive interval for ClockTicker.Reset", func() { ticker.Reset(0) })
+		require.PanicsWithValue(t, "tpp: negative duration for Clock.Advance", func() { c.Advance(-time.Second) })
+
+		c.Sleep(-time.Second)
+		require.Equal(t, epoch, c.Now())
+	})
+}
+
+func TestAdvanceBy(t *testing.T) {
+	// retry calls DoThing until it succeeds, backing off for a second more
+	// each time, or gives up after 5s.
+	retry := func(c *tpp.Clock, thing *testdata.MockIntyThing) (int, error) {
+		deadline := c.Now().Add(5 * time.Second)
+		for i := 1; ; i++ {
+			n, err := thing.DoThing(1, 2)
+			if err == nil {
+				return n, nil
+			}
+			if !c.Now().Before(deadline) {
+				return 0, err
+			}
+			c.Sleep(time.Duration(i) * time.Second)
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		doThing  []tpp.Expect
+		want     int
+		wantErr  bool
+		wantTime time.Duration
+	}{
+		{
+			name:    "OK: first time",
+			doThing: []tpp.Expect{tpp.OK(1)},
+			want:    1,
+		},
+		{
+			name: "OK: after retries",
+			doThing: []tpp.Expect{
+				tpp.Err().Once().Then(tpp.AdvanceBy(time.Second)),
+				tpp.Err().Once(),
+				tpp.OK(1).Once(),
+			},
+			want:     1,
+			wantTime: 4 * time.Second,
+		},
+		{
+			name: "ERR: deadline exceeded",
+			doThing: []tpp.Expect{
+				tpp.Err().Times(2).Then(tpp.AdvanceBy(time.Second), tpp.AdvanceBy(time.Second)),
+			},
+			wantErr:  true,
+			wantTime: 5 * time.Second,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := tpp.NewClock(epoch)
+			thing := testdata.NewMockIntyThing(t)
+			tpp.ExpectoriseMulti(tt.doThing, func() tpp.MockCall {
+				return thing.EXPECT().DoThing(1, 2)
+			}, tpp.WithClock(clock))
+
+			got, err := retry(clock, thing)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, got)
+			require.Equal(t, epoch.Add(tt.wantTime), clock.Now())
+		})
+	}
+
+	t.Run("keeps Run set before Expectorise", func(t *testing.T) {
+		clock := tpp.NewClock(epoch)
+		thing := testdata.NewMockIntyThing(t)
+
+		var ran bool
+		call := thing.EXPECT().DoThing(1, 2).Run(func(int, int) { ran = true })
+		e := tpp.OK(1).Then(tpp.AdvanceBy(time.Second))
+		e.Expectorise(call, tpp.WithClock(clock))
+
+		_, _ = thing.DoThing(1, 2)
+		require.True(t, ran)
+		require.Equal(t, epoch.Add(time.Second), clock.Now())
+	})
+
+	t.Run("panics without a clock", func(t *testing.T) {
+		thing := testdata.NewMockIntyThing(_t())
+		e := tpp.OK(1).Then(tpp.AdvanceBy(time.Second))
+		require.Panics(t, func() { e.Expectorise(thing.EXPECT().DoThing(1, 2)) })
+	})
+
+	t.Run("panics on negative duration", func(t *testing.T) {
+		thing := testdata.NewMockIntyThing(_t())
+		e := tpp.OK(1).Then(tpp.AdvanceBy(-time.Second))
+		require.Panics(t, func() {
+			e.Expectorise(thing.EXPECT().DoThing(1, 2), tpp.WithClock(tpp.NewClock(epoch)))
+		})
+	})
+}
+
+func requireNotFired(t *testing.T, ch <-chan time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpectedly fired at %s", got)
+	default:
+	}
+}
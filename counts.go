@@ -0,0 +1,180 @@
+package tpp
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// AtLeast returns a copy of the Expect which must be called at least n times.
+//
+// Since there's no limit on the calls, it takes all of them: in a slice given
+// to ExpectoriseMulti, Expects after it will never be called.
+//
+// Ranges are enforced by tpp rather than testify, so AtLeast takes the test to
+// fail, and to check the calls at the cleanup of. The test given to
+// Expectorise with WithTest is used instead, if there is one, since that's
+// usually more precise: the subtest for a table case, rather than the test
+// whose table it's in. The same goes for AtMost and Between.
+func (e Expect) AtLeast(t TestingT, n int) Expect {
+	return e.withRange(t, n, -1)
+}
+
+// AtMost returns a copy of the Expect which may be called at most n times. Any
+// more calls fail t, or the test given by WithTest, as for AtLeast.
+func (e Expect) AtMost(t TestingT, n int) Expect {
+	return e.withRange(t, 0, n)
+}
+
+// Between returns a copy of the Expect which must be called at least lo times,
+// and at most hi times. Otherwise it fails t, or the test given by WithTest, as
+// for AtLeast.
+func (e Expect) Between(t TestingT, lo, hi int) Expect {
+	return e.withRange(t, lo, hi)
+}
+
+// AnyTimes returns a copy of the Expect which may be called any number of
+// times, including none. Like AtLeast, it takes all of the calls. It can't
+// fail, so doesn't need the test.
+func (e Expect) AnyTimes() Expect {
+	return e.withRange(nil, 0, -1)
+}
+
+// callRange is the range of times a mock call must be made. A negative max
+// means there's no limit.
+//
+// testify only understands exact counts, with Times, so Expectorise enforces
+// ranges itself: the testify call is made optional and unlimited, a Run hook
+// counts the calls, and fails the test if there are more than max, and a
+// cleanup fails it if there were fewer than min. t is the test given to the
+// range's constructor, which is nil for AnyTimes.
+type callRange struct {
+	min, max int
+	t        TestingT
+}
+
+// unlimited returns whether the range allows any number of calls, so can't
+// fail.
+func (r callRange) unlimited() bool {
+	return r.min == 0 && r.max < 0
+}
+
+func (e Expect) withRange(t TestingT, min, max int) Expect {
+	r := callRange{min: min, max: max, t: t}
+	if min < 0 || (max >= 0 && max < min) {
+		panic(fmt.Sprintf("tpp: invalid call count range: %s", r))
+	}
+	if t == nil && !r.unlimited() {
+		panic(fmt.Sprintf("tpp: %s needs the test", r))
+	}
+
+	e.Expected = ptr(true)
+	e.nTimes = 0
+	e.callRange = &r
+	return e
+}
+
+func (r callRange) String() string {
+	switch {
+	case r.unlimited():
+		return "AnyTimes()"
+	case r.max < 0:
+		return fmt.Sprintf("AtLeast(%d)", r.min)
+	case r.min == 0:
+		return fmt.Sprintf("AtMost(%d)", r.max)
+	default:
+		return fmt.Sprintf("Between(%d,%d)", r.min, r.max)
+	}
+}
+
+// callCounter counts the calls made to a mock call, against a callRange.
+type callCounter struct {
+	callRange
+	n int64
+}
+
+// add counts a call. It's a Run hook, so takes the call's args.
+func (c *callCounter) add(testifymock.Arguments) {
+	atomic.AddInt64(&c.n, 1)
+}
+
+func (c *callCounter) count() int {
+	return int(atomic.LoadInt64(&c.n))
+}
+
+// installRange sets up the Expectation's call to enforce its Expect's
+// callRange. It fails t, the test given by WithTest, or else the range's own
+// test, and checks the minimum at its cleanup.
+func (x *Expectation) installRange(t TestingT) error {
+	call, r := x.call, x.expect.callRange
+	if call == nil {
+		return fmt.Errorf("tpp: can't enforce %s on a mock call without a *mock.Call", r)
+	}
+	if t == nil {
+		t = r.t
+	}
+
+	counter := &callCounter{callRange: *r}
+	x.counter = counter
+
+	// Unlimited, as far as testify's concerned, and optional, so that it
+	// doesn't fail on calls which haven't been made.
+	call.Times(0).Maybe()
+
+	if t == nil {
+		// AnyTimes, without WithTest: there's nothing to fail, so the calls
+		// only need counting, for WaitFor.
+		addRunHook(call, counter.add)
+		return nil
+	}
+
+	addRunHook(call, func(args testifymock.Arguments) {
+		n := int(atomic.AddInt64(&counter.n, 1))
+		if counter.max >= 0 && n > counter.max {
+			t.Errorf("tpp: %s%v called %d time(s), but expected %s", call.Method, args, n, counter.callRange)
+		}
+	})
+
+	t.Cleanup(func() {
+		if s, ok := t.(interface{ Skipped() bool }); ok && s.Skipped() {
+			return
+		}
+		if n := counter.count(); n < counter.min {
+			t.Errorf("tpp: %s%v called %d time(s), but expected %s", call.Method, call.Arguments, n, counter.callRange)
+		}
+	})
+
+	return nil
+}
+
+// countCalls counts the calls routed to the Expectation's call, so that
+// WaitFor can tell when it's been made as many times as it needs to be: its
+// Times, or else at least once. Calls which match its args but are routed to
+// another call, e.g. one set up by another Expect, aren't counted.
+func (x *Expectation) countCalls() {
+	if x.call == nil {
+		return
+	}
+
+	min := 1
+	if x.expect.nTimes > min {
+		min = x.expect.nTimes
+	}
+
+	counter := &callCounter{callRange: callRange{min: min, max: -1}}
+	x.counter = counter
+
+	addRunHook(x.call, counter.add)
+}
+
+// callsMade returns whether the Expectation's mock call has been made as many
+// times as it needs to be, along with a description of how many times it has
+// been. Its calls must be counted, by countCalls or installRange.
+func (x *Expectation) callsMade() (string, bool) {
+	n := x.counter.count()
+	if n < x.counter.min {
+		return fmt.Sprintf("%d more time(s) needed", x.counter.min-n), false
+	}
+	return fmt.Sprintf("called %d time(s)", n), true
+}
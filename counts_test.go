@@ -0,0 +1,104 @@
+package tpp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestCallCounts(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		expect     tpp.Expect
+		calls      int
+		wantFailed bool
+	}{
+		{name: "AtLeast: fewer", expect: tpp.OK(1).AtLeast(t, 2), calls: 1, wantFailed: true},
+		{name: "AtLeast: exactly", expect: tpp.OK(1).AtLeast(t, 2), calls: 2},
+		{name: "AtLeast: more", expect: tpp.OK(1).AtLeast(t, 2), calls: 5},
+		{name: "AtMost: none", expect: tpp.OK(1).AtMost(t, 2), calls: 0},
+		{name: "AtMost: exactly", expect: tpp.OK(1).AtMost(t, 2), calls: 2},
+		{name: "AtMost: more", expect: tpp.OK(1).AtMost(t, 2), calls: 3, wantFailed: true},
+		{name: "AtMost(0): called", expect: tpp.OK(1).AtMost(t, 0), calls: 1, wantFailed: true},
+		{name: "Between: fewer", expect: tpp.OK(1).Between(t, 2, 3), calls: 1, wantFailed: true},
+		{name: "Between: lo", expect: tpp.OK(1).Between(t, 2, 3), calls: 2},
+		{name: "Between: hi", expect: tpp.OK(1).Between(t, 2, 3), calls: 3},
+		{name: "Between: more", expect: tpp.OK(1).Between(t, 2, 3), calls: 4, wantFailed: true},
+		{name: "AnyTimes: none", expect: tpp.OK(1).AnyTimes(), calls: 0},
+		{name: "AnyTimes: many", expect: tpp.OK(1).AnyTimes(), calls: 10},
+		{name: "Err: AtLeast", expect: tpp.Err().AtLeast(t, 1), calls: 1},
+		{name: "Times replaces range", expect: tpp.OK(1).AtLeast(t, 5).Times(1), calls: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := &cleanupT{}
+			mock := testdata.NewMockIntyThing(ct)
+			tt.expect.Expectorise(mock.EXPECT().DoThing(1, 2), tpp.WithTest(ct))
+
+			for i := 0; i < tt.calls; i++ {
+				n, err := mock.DoThing(1, 2)
+				if tt.expect.Err == nil {
+					require.NoError(t, err)
+					require.Equal(t, 1, n)
+				}
+			}
+
+			ct.runCleanups()
+			require.Equal(t, tt.wantFailed, ct.failed, ct.msgs)
+		})
+	}
+
+	t.Run("failure message", func(t *testing.T) {
+		ct := &cleanupT{}
+		mock := testdata.NewMockIntyThing(ct)
+		e := tpp.OK(1).Between(t, 2, 3)
+		e.Expectorise(mock.EXPECT().DoThing(1, 2), tpp.WithTest(ct))
+
+		_, _ = mock.DoThing(1, 2)
+		ct.runCleanups()
+		require.Equal(t, []string{"tpp: DoThing[1 2] called 1 time(s), but expected Between(2,3)"}, ct.msgs)
+	})
+
+	t.Run("WaitFor waits for minimum", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		e := tpp.OK(1).AtLeast(t, 3)
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2), tpp.WithTest(t))
+
+		go func() {
+			for i := 0; i < 3; i++ {
+				time.Sleep(time.Millisecond)
+				_, _ = mock.DoThing(1, 2)
+			}
+		}()
+
+		require.True(t, tpp.WaitFor(t, time.Second, x))
+	})
+
+	t.Run("panics on invalid range", func(t *testing.T) {
+		require.Panics(t, func() { tpp.OK().Between(t, 3, 2) })
+		require.Panics(t, func() { tpp.OK().AtLeast(t, -1) })
+	})
+
+	t.Run("fails the range's test without WithTest", func(t *testing.T) {
+		ct := &cleanupT{}
+		mock := testdata.NewMockIntyThing(_t())
+		e := tpp.OK(1).AtLeast(ct, 2)
+		e.Expectorise(mock.EXPECT().DoThing(1, 2))
+
+		_, _ = mock.DoThing(1, 2)
+		ct.runCleanups()
+		require.Equal(t, []string{"tpp: DoThing[1 2] called 1 time(s), but expected AtLeast(2)"}, ct.msgs)
+	})
+
+	t.Run("AnyTimes without WithTest", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		e := tpp.OK(1).AnyTimes()
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2))
+
+		_, _ = mock.DoThing(1, 2)
+		require.True(t, tpp.WaitFor(t, time.Second, x))
+	})
+}
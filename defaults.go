@@ -0,0 +1,145 @@
+package tpp
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// RegisterDefaults registers default returns for a mock method, for the whole
+// package. This saves repeating WithDefaultReturns at every Expectorise for
+// methods whose zero values are nonsensical, such as a nil *Struct. It's best
+// called from an init func or TestMain. For example:
+//
+//	func init() {
+//		tpp.RegisterDefaults((*mymocks.FooGetter_GetFoo_Call)(nil), &Foo{})
+//	}
+//
+// The key is either a mock call, as above, whose type identifies the method,
+// or the name of the method, e.g. "GetFoo", which matches the method of that
+// name on any mock. As with WithDefaultReturns, the returns are used as they
+// are, and must match the mock's Return method.
+//
+// The returns for a mock call come from, in order of precedence:
+//
+//  1. The Expect, if it has returns or an error.
+//  2. WithDefaultReturns.
+//  3. RegisterTestDefaults for the test given by WithTest, or else its closest
+//     parent.
+//  4. RegisterDefaults.
+//  5. Zero values.
+//
+// Within each registry, defaults keyed by mock call take precedence over those
+// keyed by method name. Registering the same key again replaces its defaults.
+func RegisterDefaults(key any, returns ...any) {
+	k := defaultsKeyOf(key)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.pkg[k] = returns
+}
+
+// RegisterTestDefaults is like RegisterDefaults, but the defaults only apply to
+// Expectorise calls given the test or one of its subtests with WithTest, until
+// it finishes. They take precedence over the package's defaults, and those of
+// the test's parents.
+func RegisterTestDefaults(t testing.TB, key any, returns ...any) {
+	k, name := defaultsKeyOf(key), t.Name()
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.tests[name] == nil {
+		registry.tests[name] = make(map[defaultsKey][]any)
+		t.Cleanup(func() {
+			registry.mu.Lock()
+			defer registry.mu.Unlock()
+			delete(registry.tests, name)
+		})
+	}
+	registry.tests[name][k] = returns
+}
+
+// registry holds the defaults registered by RegisterDefaults, and by
+// RegisterTestDefaults keyed by test name.
+var registry = struct {
+	mu    sync.Mutex
+	pkg   map[defaultsKey][]any
+	tests map[string]map[defaultsKey][]any
+}{
+	pkg:   make(map[defaultsKey][]any),
+	tests: make(map[string]map[defaultsKey][]any),
+}
+
+// defaultsKey identifies a mock method, either by the type of its mock call or
+// by its name.
+type defaultsKey struct {
+	callType reflect.Type
+	method   string
+}
+
+func defaultsKeyOf(key any) defaultsKey {
+	switch k := key.(type) {
+	case nil:
+		panic("tpp: can't register defaults for a nil key")
+	case string:
+		return defaultsKey{method: k}
+	default:
+		return defaultsKey{callType: reflect.TypeOf(k)}
+	}
+}
+
+// registeredDefaults returns the registered defaults for the mock call in the
+// named test, or nil if there aren't any. The name may be "" if the test isn't
+// known, in which case only the package's defaults apply.
+func registeredDefaults(mock MockCall, call *testifymock.Call, name string) []any {
+	keys := []defaultsKey{{callType: reflect.TypeOf(mock)}}
+	if call != nil {
+		keys = append(keys, defaultsKey{method: call.Method})
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for name != "" {
+		for _, k := range keys {
+			if returns, ok := registry.tests[name][k]; ok {
+				return returns
+			}
+		}
+
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	for _, k := range keys {
+		if returns, ok := registry.pkg[k]; ok {
+			return returns
+		}
+	}
+	return nil
+}
+
+// defaultsFor returns the default returns for the mock call: those given by
+// WithDefaultReturns, or else those registered. It's nil if there are none.
+func (opts *expectoriseOptions) defaultsFor(mock MockCall, call *testifymock.Call) []any {
+	if opts.defaultReturns != nil {
+		return opts.defaultReturns
+	}
+	return registeredDefaults(mock, call, opts.testName())
+}
+
+// testName returns the name of the test given by WithTest, or "" if there
+// isn't one.
+func (opts *expectoriseOptions) testName() string {
+	if named, ok := opts.test.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package tpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp/testdata"
+)
+
+// withPackageDefaults registers package defaults for the rest of the test.
+func withPackageDefaults(t *testing.T, key any, returns ...any) {
+	old := registry.pkg
+	registry.pkg = make(map[defaultsKey][]any)
+	t.Cleanup(func() { registry.pkg = old })
+
+	RegisterDefaults(key, returns...)
+}
+
+func TestRegisterDefaults(t *testing.T) {
+	callKey := (*testdata.MockStructyThing_DoThing_Call)(nil)
+
+	// doThing sets up the mock with the Expect, and returns what it returns.
+	doThing := func(t *testing.T, e Expect, options ...ExpectoriseOption) (*testdata.Struct, error) {
+		mock := testdata.NewMockStructyThing(t)
+		e.Expectorise(mock.EXPECT().DoThing(Arg(), Arg()), append([]ExpectoriseOption{WithTest(t)}, options...)...)
+		return mock.DoThing(nil, nil)
+	}
+
+	t.Run("package defaults", func(t *testing.T) {
+		withPackageDefaults(t, callKey, &testdata.Struct{A: 1}, nil)
+
+		got, err := doThing(t, OK())
+		require.NoError(t, err)
+		require.Equal(t, &testdata.Struct{A: 1}, got)
+	})
+
+	t.Run("by method name", func(t *testing.T) {
+		withPackageDefaults(t, "DoThing", &testdata.Struct{A: 1}, nil)
+
+		got, _ := doThing(t, Expect{})
+		require.Equal(t, &testdata.Struct{A: 1}, got)
+	})
+
+	t.Run("test defaults", func(t *testing.T) {
+		RegisterTestDefaults(t, callKey, &testdata.Struct{A: 2}, nil)
+
+		got, _ := doThing(t, OK())
+		require.Equal(t, &testdata.Struct{A: 2}, got)
+
+		t.Run("apply to subtests", func(t *testing.T) {
+			got, _ := doThing(t, OK())
+			require.Equal(t, &testdata.Struct{A: 2}, got)
+		})
+	})
+
+	t.Run("test defaults need WithTest", func(t *testing.T) {
+		RegisterTestDefaults(t, callKey, &testdata.Struct{A: 2}, nil)
+
+		mock := testdata.NewMockStructyThing(t)
+		e := OK()
+		e.Expectorise(mock.EXPECT().DoThing(Arg(), Arg()))
+
+		got, _ := mock.DoThing(nil, nil)
+		require.Nil(t, got)
+	})
+
+	t.Run("test defaults end with the test", func(t *testing.T) {
+		t.Run("register", func(t *testing.T) {
+			RegisterTestDefaults(t, callKey, &testdata.Struct{A: 2}, nil)
+		})
+
+		got, _ := doThing(t, OK())
+		require.Nil(t, got)
+	})
+
+	t.Run("ExpectoriseMulti with nil slice", func(t *testing.T) {
+		RegisterTestDefaults(t, callKey, &testdata.Struct{A: 2}, nil)
+
+		mock := testdata.NewMockStructyThing(t)
+		ExpectoriseMulti(nil, func() MockCall {
+			return mock.EXPECT().DoThing(Arg(), Arg())
+		}, WithTest(t))
+
+		got, _ := mock.DoThing(nil, nil)
+		require.Equal(t, &testdata.Struct{A: 2}, got)
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		withPackageDefaults(t, callKey, &testdata.Struct{A: 1}, nil)
+		RegisterTestDefaults(t, "DoThing", &testdata.Struct{A: 2}, nil)
+
+		for _, tt := range []struct {
+			name    string
+			expect  Expect
+			options []ExpectoriseOption
+			setup   func(t *testing.T)
+			want    *testdata.Struct
+			wantErr bool
+		}{
+			{
+				name:   "Expect returns beat everything",
+				expect: OK(&testdata.Struct{A: 9}),
+				want:   &testdata.Struct{A: 9},
+			},
+			{
+				name:    "Expect errors beat everything",
+				expect:  Err(),
+				wantErr: true,
+			},
+			{
+				name:    "WithDefaultReturns beats registries",
+				expect:  OK(),
+				options: []ExpectoriseOption{WithDefaultReturns(&testdata.Struct{A: 8}, nil)},
+				want:    &testdata.Struct{A: 8},
+			},
+			{
+				name:   "test beats package",
+				expect: OK(),
+				want:   &testdata.Struct{A: 2},
+			},
+			{
+				name:   "subtest beats test",
+				expect: OK(),
+				setup: func(t *testing.T) {
+					RegisterTestDefaults(t, "DoThing", &testdata.Struct{A: 3}, nil)
+				},
+				want: &testdata.Struct{A: 3},
+			},
+			{
+				name:   "mock call beats method name",
+				expect: OK(),
+				setup: func(t *testing.T) {
+					RegisterTestDefaults(t, "DoThing", &testdata.Struct{A: 3}, nil)
+					RegisterTestDefaults(t, callKey, &testdata.Struct{A: 4}, nil)
+				},
+				want: &testdata.Struct{A: 4},
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.setup != nil {
+					tt.setup(t)
+				}
+
+				got, err := doThing(t, tt.expect, tt.options...)
+				require.Equal(t, tt.wantErr, err != nil)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("zero values without defaults", func(t *testing.T) {
+		got, err := doThing(t, OK())
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
@@ -0,0 +1,184 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Derive returns a copy of the base test case with the given name, changed by
+// mutate. This saves repeating every field of the base case in rows which only
+// differ from it in one or two ways. For example:
+//
+//	ok := testCase{
+//		name:    "OK",
+//		getFoo:  tpp.OK(foo),
+//		saveFoo: tpp.OK(),
+//		want:    tpp.WantValue(foo),
+//	}
+//
+//	tests := []testCase{
+//		ok,
+//		tpp.Derive(ok, "ERR: getFoo", func(tt *testCase) {
+//			tt.getFoo = tpp.Err()
+//			tt.saveFoo = tpp.Unexpected()
+//			tt.want = tpp.WantAny[*Foo]()
+//		}),
+//	}
+//
+// The base is copied deeply, so that mutate can change its slices and maps,
+// including []Expects and the returns of Expects, without changing the base.
+// Pointers are shared, as are the values in interfaces.
+//
+// The name is set on the test case's "name" or "Name" field. If it's empty,
+// the name is left as it is in the base. Derive panics if it's given a name
+// but the test case has no name field.
+func Derive[T any](base T, name string, mutate func(*T)) T {
+	v := deepCopyCase(base)
+
+	if name != "" {
+		idx := caseNameField(v.Type())
+		if idx < 0 {
+			panic(fmt.Sprintf("tpp: Derive given a name, but %s has no name field", v.Type()))
+		}
+		caseField(v, idx).SetString(name)
+	}
+
+	tc := v.Addr().Interface().(*T)
+	if mutate != nil {
+		mutate(tc)
+	}
+	return *tc
+}
+
+// Override returns a copy of the base test case with the fields named by the
+// keys of overrides set to their values. For example:
+//
+//	tpp.Override(ok, map[string]any{
+//		"getFoo":  tpp.Err(),
+//		"saveFoo": tpp.Unexpected(),
+//	})
+//
+// As with Derive, the base is copied deeply. A nil value sets the field to its
+// zero value.
+//
+// If the test case has a name field which isn't overridden, it's set to
+// describe the overrides, e.g. "getFoo=Err/saveFoo=Unexpected" as with
+// NameCase.
+//
+// Override panics if a key isn't a field of the test case, or if its value
+// can't be assigned to the field.
+func Override[T any](base T, overrides map[string]any) T {
+	v := deepCopyCase(base)
+	typ := v.Type()
+
+	// Set the fields in declaration order, so that the name is deterministic.
+	var fields []int
+	for key := range overrides {
+		f, ok := typ.FieldByName(key)
+		if !ok || len(f.Index) != 1 {
+			panic(fmt.Sprintf("tpp: Override field %q does not exist on %s", key, typ))
+		}
+		fields = append(fields, f.Index[0])
+	}
+	sort.Ints(fields)
+
+	var desc []string
+	for _, i := range fields {
+		key, field := typ.Field(i).Name, caseField(v, i)
+
+		val := overrides[key]
+		switch {
+		case val == nil:
+			field.Set(reflect.Zero(field.Type()))
+		case reflect.TypeOf(val).AssignableTo(field.Type()):
+			field.Set(reflect.ValueOf(val))
+		default:
+			panic(fmt.Sprintf("tpp: Override field %q is %s, but was given %T", key, field.Type(), val))
+		}
+
+		desc = append(desc, describeField(key, field))
+	}
+
+	if idx := caseNameField(typ); idx >= 0 {
+		if _, ok := overrides[typ.Field(idx).Name]; !ok {
+			caseField(v, idx).SetString(strings.Join(desc, "/"))
+		}
+	}
+
+	return v.Interface().(T)
+}
+
+// describeField describes a field of a test case for Override's names: Expect
+// fields as NameCase does, and others just by name.
+func describeField(name string, f reflect.Value) string {
+	switch f.Type() {
+	case expectType:
+		return name + "=" + f.Interface().(Expect).describe()
+	case expectSliceType:
+		if ee := f.Interface().([]Expect); ee != nil {
+			return name + "=" + describeMulti(ee)
+		}
+	default:
+		if f.Type().Implements(typedExpectType) {
+			return name + "=" + f.Interface().(typedExpect).Expect().describe()
+		}
+	}
+	return name
+}
+
+// deepCopyCase returns an addressable deep copy of the given test case, which
+// must be a struct.
+func deepCopyCase(tc any) reflect.Value {
+	src := caseValue(tc)
+	dst := reflect.New(src.Type()).Elem()
+	deepCopy(dst, src)
+	return dst
+}
+
+// deepCopy copies src into dst, copying slices, maps, arrays and structs
+// deeply. Both must be addressable, or be slice elements, so that unexported
+// struct fields can be read and written.
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			deepCopy(caseField(dst, i), caseField(src, i))
+		}
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			// Map values aren't addressable, so copy them somewhere which is.
+			val := reflect.New(src.Type().Elem()).Elem()
+			val.Set(iter.Value())
+			cp := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(cp, val)
+			m.SetMapIndex(iter.Key(), cp)
+		}
+		dst.Set(m)
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,113 @@
+package tpp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+type deriveCase struct {
+	name     string
+	getFoo   tpp.Expect
+	saveFoos []tpp.Expect
+	args     map[string][]int
+	want     *testdata.Struct
+	wantErr  bool
+}
+
+func okCase() deriveCase {
+	return deriveCase{
+		name:     "OK",
+		getFoo:   tpp.OK(1, 2),
+		saveFoos: []tpp.Expect{tpp.OK(), tpp.OK()},
+		args:     map[string][]int{"a": {1, 2}},
+		want:     &testdata.Struct{A: 1},
+	}
+}
+
+func TestDerive(t *testing.T) {
+	t.Run("sets name and mutates", func(t *testing.T) {
+		base := okCase()
+		tc := tpp.Derive(base, "ERR: getFoo", func(tt *deriveCase) {
+			tt.getFoo = tpp.Err()
+			tt.wantErr = true
+		})
+
+		require.Equal(t, "ERR: getFoo", tc.name)
+		require.NotNil(t, tc.getFoo.Err)
+		require.True(t, tc.wantErr)
+		require.Equal(t, base.saveFoos, tc.saveFoos)
+		require.Equal(t, okCase(), base)
+	})
+
+	t.Run("copies deeply", func(t *testing.T) {
+		base := okCase()
+		tpp.Derive(base, "", func(tt *deriveCase) {
+			tt.getFoo.Return[0] = 9
+			tt.saveFoos[0] = tpp.Err()
+			tt.args["a"][0] = 9
+			tt.args["b"] = nil
+		})
+
+		require.Equal(t, okCase(), base)
+	})
+
+	t.Run("shares pointers", func(t *testing.T) {
+		base := okCase()
+		tc := tpp.Derive(base, "", nil)
+		require.Same(t, base.want, tc.want)
+		require.Equal(t, "OK", tc.name)
+	})
+
+	t.Run("panics with name but no name field", func(t *testing.T) {
+		require.Panics(t, func() { tpp.Derive(struct{ getFoo tpp.Expect }{}, "foo", nil) })
+	})
+}
+
+func TestOverride(t *testing.T) {
+	t.Run("sets fields and names the case", func(t *testing.T) {
+		base := okCase()
+		tc := tpp.Override(base, map[string]any{
+			"saveFoos": []tpp.Expect{},
+			"getFoo":   tpp.Err(),
+			"wantErr":  true,
+			"want":     nil,
+		})
+
+		require.Equal(t, "getFoo=Err/saveFoos=[]/want/wantErr", tc.name)
+		require.NotNil(t, tc.getFoo.Err)
+		require.Empty(t, tc.saveFoos)
+		require.Nil(t, tc.want)
+		require.True(t, tc.wantErr)
+		require.Equal(t, okCase(), base)
+	})
+
+	t.Run("keeps given name", func(t *testing.T) {
+		tc := tpp.Override(okCase(), map[string]any{"name": "foo", "getFoo": tpp.Err()})
+		require.Equal(t, "foo", tc.name)
+	})
+
+	t.Run("copies deeply", func(t *testing.T) {
+		base := okCase()
+		tc := tpp.Override(base, map[string]any{"wantErr": true})
+		tc.args["a"][0] = 9
+		tc.saveFoos[0] = tpp.Err()
+
+		require.Equal(t, okCase(), base)
+	})
+
+	t.Run("panics on unknown field", func(t *testing.T) {
+		require.PanicsWithValue(t, `tpp: Override field "getBar" does not exist on tpp_test.deriveCase`, func() {
+			tpp.Override(okCase(), map[string]any{"getBar": tpp.Err()})
+		})
+	})
+
+	t.Run("panics on wrong type", func(t *testing.T) {
+		require.PanicsWithValue(t, `tpp: Override field "getFoo" is tpp.Expect, but was given []tpp.Expect`, func() {
+			tpp.Override(okCase(), map[string]any{"getFoo": []tpp.Expect{}})
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package tpp
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// Effect is a side effect of a mock call, such as AdvanceBy. Effects are added
+// to an Expect with Then, and happen each time the mock is called, before it
+// returns.
+type Effect struct {
+	desc string
+
+	// bind returns the func which makes the effect happen, given the options
+	// passed to Expectorise.
+	bind func(opts *expectoriseOptions) (func(), error)
+}
+
+// Then returns a copy of the Expect with the given effects, which happen in
+// order each time the mock is called.
+func (e Expect) Then(effects ...Effect) Expect {
+	e.effects = append(append([]Effect{}, e.effects...), effects...)
+	return e
+}
+
+// installEffects sets up the Expect's effects to happen when the call is made.
+func (e *Expect) installEffects(call *testifymock.Call, opts *expectoriseOptions) error {
+	if len(e.effects) == 0 {
+		return nil
+	}
+
+	if call == nil {
+		return errors.New("tpp: can't add effects to a mock call without a *mock.Call")
+	}
+
+	var fns []func()
+	for _, eff := range e.effects {
+		fn, err := eff.bind(opts)
+		if err != nil {
+			return err
+		}
+		fns = append(fns, fn)
+	}
+
+	addRunHook(call, func(testifymock.Arguments) {
+		for _, fn := range fns {
+			fn()
+		}
+	})
+	return nil
+}
+
+// addRunHook adds fn to be run whenever the call is made, after any Run func
+// which is already set on it. So, effects are kept if the meta-test sets Run
+// before Expectorise, but not after.
+func addRunHook(call *testifymock.Call, fn func(testifymock.Arguments)) {
+	prev := call.RunFn
+	if prev == nil {
+		call.Run(fn)
+		return
+	}
+
+	call.Run(func(args testifymock.Arguments) {
+		prev(args)
+		fn(args)
+	})
+}
+
+// describeEffects returns a short description of the effects, for describe.
+func describeEffects(effects []Effect) string {
+	descs := make([]string, len(effects))
+	for i, eff := range effects {
+		descs[i] = eff.desc
+	}
+	return strings.Join(descs, ",")
+}
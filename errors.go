@@ -0,0 +1,92 @@
+package tpp
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+// expectError is the error returned by mocks configured with Err().
+//
+// Each Err() gets its own expectError, so that tests can tell whether the error
+// returned by the subject came from, say, getFoo or saveFoo. They're labelled
+// with the call site of Err(), which in a table is the field it's assigned to.
+type expectError struct {
+	site string
+}
+
+func (e *expectError) Error() string {
+	return fmt.Sprintf("ERROR (tpp.Err() at %s)", e.site)
+}
+
+// errExpect returns an Expect with a new expectError, labelled with the call
+// site |skip| frames above the caller.
+func errExpect(skip int) Expect {
+	site := "unknown"
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		site = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	return Expect{
+		Expected: ptr(true),
+		Err:      &expectError{site: site},
+	}
+}
+
+// isExpectError returns whether err was created by Err().
+func isExpectError(err error) bool {
+	_, ok := err.(*expectError)
+	return ok
+}
+
+// RequireErrFrom asserts that err is, or wraps, the error configured on the
+// Expect, as checked by errors.Is.
+//
+// This is useful for checking that the subject propagates the error from the
+// right dependency. For example:
+//
+//	err := subject.XXX()
+//	if tt.getFoo.Err != nil {
+//		tpp.RequireErrFrom(t, err, tt.getFoo)
+//	}
+//
+// It fails the test if the Expect has no error.
+func RequireErrFrom(t require.TestingT, err error, e Expect) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if e.Err == nil {
+		t.Errorf("tpp: RequireErrFrom called with an Expect which has no error")
+		t.FailNow()
+		return
+	}
+
+	require.ErrorIs(t, err, e.Err)
+}
+
+// RequireWrapped asserts that err wraps the error configured on the Expect, and
+// is not the error itself. I.e., that the subject has added context to the
+// error, e.g. with fmt.Errorf("...: %w", err).
+//
+// It fails the test if the Expect has no error.
+func RequireWrapped(t require.TestingT, err error, e Expect) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	RequireErrFrom(t, err, e)
+
+	if err == e.Err {
+		t.Errorf("tpp: expected error to be wrapped with context, but got it as is: %v", err)
+		t.FailNow()
+	}
+}
+
+// tHelper is implemented by *testing.T, and lets us mark our assertion helpers
+// as such.
+type tHelper interface {
+	Helper()
+}
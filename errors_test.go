@@ -0,0 +1,130 @@
+package tpp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestErr(t *testing.T) {
+	t.Run("Err() is distinct per call", func(t *testing.T) {
+		e1, e2 := tpp.Err(), tpp.Err()
+		require.True(t, e1.Err != e2.Err)
+		require.False(t, errors.Is(e1.Err, e2.Err))
+	})
+
+	t.Run("Err() is labelled with call site", func(t *testing.T) {
+		e := tpp.Err()
+		require.Contains(t, e.Err.Error(), "errors_test.go:")
+	})
+
+	t.Run("Err1() is labelled with call site", func(t *testing.T) {
+		e := tpp.Err1[int]()
+		require.Contains(t, e.Expect().Err.Error(), "errors_test.go:")
+	})
+
+	t.Run("Err() is returned by mock", func(t *testing.T) {
+		e := tpp.Err()
+		mock := testdata.NewMockIntyThing(_t())
+		e.Expectorise(mock.EXPECT().DoThing(1, 2))
+
+		_, err := mock.DoThing(1, 2)
+		require.Equal(t, e.Err, err)
+	})
+}
+
+func TestRequireErrFrom(t *testing.T) {
+	getFoo, saveFoo := tpp.Err(), tpp.Err()
+
+	for _, tt := range []struct {
+		name       string
+		err        error
+		expect     tpp.Expect
+		wantFailed bool
+	}{
+		{
+			name:   "OK: same error",
+			err:    getFoo.Err,
+			expect: getFoo,
+		},
+		{
+			name:   "OK: wrapped error",
+			err:    fmt.Errorf("getting foo: %w", getFoo.Err),
+			expect: getFoo,
+		},
+		{
+			name:   "OK: ErrWith",
+			err:    errors.Wrap(errTest, "getting foo"),
+			expect: tpp.ErrWith(errTest),
+		},
+		{
+			name:       "ERR: error from other Expect",
+			err:        saveFoo.Err,
+			expect:     getFoo,
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: nil error",
+			err:        nil,
+			expect:     getFoo,
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: Expect without error",
+			err:        getFoo.Err,
+			expect:     tpp.OK(),
+			wantFailed: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			tpp.RequireErrFrom(ft, tt.err, tt.expect)
+			require.Equal(t, tt.wantFailed, ft.failed)
+		})
+	}
+}
+
+func TestRequireWrapped(t *testing.T) {
+	getFoo, saveFoo := tpp.Err(), tpp.Err()
+
+	for _, tt := range []struct {
+		name       string
+		err        error
+		wantFailed bool
+	}{
+		{
+			name: "OK: wrapped with fmt",
+			err:  fmt.Errorf("getting foo: %w", getFoo.Err),
+		},
+		{
+			name: "OK: wrapped with pkg/errors",
+			err:  errors.Wrap(getFoo.Err, "getting foo"),
+		},
+		{
+			name:       "ERR: not wrapped",
+			err:        getFoo.Err,
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: context added without %w",
+			err:        fmt.Errorf("getting foo: %v", getFoo.Err),
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: wraps other error",
+			err:        fmt.Errorf("saving foo: %w", saveFoo.Err),
+			wantFailed: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			tpp.RequireWrapped(ft, tt.err, getFoo)
+			require.Equal(t, tt.wantFailed, ft.failed)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// StatefulFake is an in-memory store of K to V, which the Get, Put and Delete
+// methods of a repository-style mock can be bound to, so that Get returns what
+// Put stored. For example:
+//
+//	fake := tpp.NewStatefulFake(tt.initial).NotFound(ErrNotFound)
+//
+//	tt.getFoo.Expectorise(mock.EXPECT().GetFoo(tpp.Arg(), tpp.Arg()), fake.Get())
+//	tt.putFoo.Expectorise(mock.EXPECT().PutFoo(tpp.Arg(), tpp.Arg(), tpp.Arg()), fake.Put())
+//
+//	...
+//
+//	require.Equal(t, tt.wantState, fake.State())
+//
+// The methods are bound with mockery's RunAndReturn, so only work with mockery
+// mocks. They're only bound when the Expect doesn't give its own returns or
+// error, so a row can still use, say, tpp.Err() or tpp.Unexpected() for one
+// method, or tpp.OK() to require that it's called.
+//
+// Methods are bound by their shape. The key is the first arg of type K, and
+// Put's value is the first other arg of type V. Get returns the value in its
+// first return of type V, and whether it was found in any bool return. Get and
+// Delete return the NotFound error, if any, when the key isn't there. Any other
+// returns are zero valued.
+//
+// It's safe for concurrent use.
+type StatefulFake[K comparable, V any] struct {
+	mu       sync.Mutex
+	state    map[K]V
+	notFound error
+}
+
+// NewStatefulFake returns a StatefulFake with a copy of the given initial
+// state, which may be nil.
+func NewStatefulFake[K comparable, V any](initial map[K]V) *StatefulFake[K, V] {
+	f := &StatefulFake[K, V]{state: make(map[K]V, len(initial))}
+	for k, v := range initial {
+		f.state[k] = v
+	}
+	return f
+}
+
+// NotFound sets the error which Get and Delete return when the key isn't
+// there. Without it, they return no error.
+func (f *StatefulFake[K, V]) NotFound(err error) *StatefulFake[K, V] {
+	f.notFound = err
+	return f
+}
+
+// State returns a copy of the current state.
+func (f *StatefulFake[K, V]) State() map[K]V {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state := make(map[K]V, len(f.state))
+	for k, v := range f.state {
+		state[k] = v
+	}
+	return state
+}
+
+// Get binds a Get-shaped mock method to the fake, returning the value stored
+// for the key.
+func (f *StatefulFake[K, V]) Get() ExpectoriseOption {
+	return f.bind("Get", func(fnType reflect.Type) (func([]reflect.Value) []reflect.Value, error) {
+		key, err := f.argIndex(fnType, typeOf[K](), -1)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(args []reflect.Value) []reflect.Value {
+			k := valueOf[K](args[key])
+
+			f.mu.Lock()
+			v, ok := f.state[k]
+			f.mu.Unlock()
+
+			return f.returns(fnType, reflect.ValueOf(&v).Elem(), ok)
+		}, nil
+	})
+}
+
+// Put binds a Put-shaped mock method to the fake, storing the value for the
+// key.
+func (f *StatefulFake[K, V]) Put() ExpectoriseOption {
+	return f.bind("Put", func(fnType reflect.Type) (func([]reflect.Value) []reflect.Value, error) {
+		key, err := f.argIndex(fnType, typeOf[K](), -1)
+		if err != nil {
+			return nil, err
+		}
+		val, err := f.argIndex(fnType, typeOf[V](), key)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(args []reflect.Value) []reflect.Value {
+			k, v := valueOf[K](args[key]), valueOf[V](args[val])
+
+			f.mu.Lock()
+			f.state[k] = v
+			f.mu.Unlock()
+
+			return f.returns(fnType, reflect.Value{}, true)
+		}, nil
+	})
+}
+
+// Delete binds a Delete-shaped mock method to the fake, removing the key.
+func (f *StatefulFake[K, V]) Delete() ExpectoriseOption {
+	return f.bind("Delete", func(fnType reflect.Type) (func([]reflect.Value) []reflect.Value, error) {
+		key, err := f.argIndex(fnType, typeOf[K](), -1)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(args []reflect.Value) []reflect.Value {
+			k := valueOf[K](args[key])
+
+			f.mu.Lock()
+			_, ok := f.state[k]
+			delete(f.state, k)
+			f.mu.Unlock()
+
+			return f.returns(fnType, reflect.Value{}, ok)
+		}, nil
+	})
+}
+
+// bind returns an ExpectoriseOption which binds the mock's RunAndReturn to the
+// func made by makeFn.
+func (f *StatefulFake[K, V]) bind(
+	op string,
+	makeFn func(fnType reflect.Type) (func([]reflect.Value) []reflect.Value, error),
+) ExpectoriseOption {
+	return func(opts *expectoriseOptions) {
+		opts.runAndReturn = func(fnType reflect.Type) (reflect.Value, error) {
+			fn, err := makeFn(fnType)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("tpp: can't bind %s to StatefulFake.%s: %w", fnType, op, err)
+			}
+			return reflect.MakeFunc(fnType, fn), nil
+		}
+	}
+}
+
+// argIndex returns the index of the first arg of the given type, other than
+// the one at skip.
+func (f *StatefulFake[K, V]) argIndex(fnType reflect.Type, typ reflect.Type, skip int) (int, error) {
+	for i := 0; i < fnType.NumIn(); i++ {
+		if i != skip && fnType.In(i) == typ {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no %s arg", typ)
+}
+
+// returns returns the values for the mock method to return: the value, if
+// valid, in the first return of type V; found in any bool returns; NotFound in
+// any error returns if not found; and zero values otherwise.
+func (f *StatefulFake[K, V]) returns(fnType reflect.Type, value reflect.Value, found bool) []reflect.Value {
+	out := make([]reflect.Value, fnType.NumOut())
+	for i := range out {
+		typ := fnType.Out(i)
+		switch {
+		case value.IsValid() && typ == value.Type():
+			out[i], value = value, reflect.Value{}
+		case typ.Kind() == reflect.Bool:
+			out[i] = reflect.ValueOf(found).Convert(typ)
+		case isErrorType(typ) && !found && f.notFound != nil && reflect.TypeOf(f.notFound).AssignableTo(typ):
+			out[i] = reflect.ValueOf(f.notFound)
+		default:
+			out[i] = reflect.Zero(typ)
+		}
+	}
+	return out
+}
+
+// typeOf returns the reflect.Type of T.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// valueOf returns v as a T. v may be a nil interface, which can't be asserted
+// to T directly.
+func valueOf[T any](v reflect.Value) T {
+	var t T
+	if v.IsValid() && !(v.Kind() == reflect.Interface && v.IsNil()) {
+		t = v.Interface().(T)
+	}
+	return t
+}
@@ -0,0 +1,146 @@
+package tpp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+var errNotFound = errors.New("not found")
+
+// rename is a subject which moves a value from one key of a repo to another.
+func rename(ctx context.Context, repo testdata.Repo[int], from, to string) error {
+	v, err := repo.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	if err := repo.Put(ctx, to, v); err != nil {
+		return err
+	}
+	_, err = repo.Delete(ctx, from)
+	return err
+}
+
+func TestStatefulFake(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		initial   map[string]int
+		get       tpp.Expect
+		put       tpp.Expect
+		del       tpp.Expect
+		wantErr   bool
+		wantState map[string]int
+	}{
+		{
+			name:      "OK",
+			initial:   map[string]int{"a": 1, "c": 3},
+			wantState: map[string]int{"b": 1, "c": 3},
+		},
+		{
+			name:      "OK: calls required",
+			initial:   map[string]int{"a": 1},
+			get:       tpp.OK(),
+			put:       tpp.OK(),
+			del:       tpp.OK(),
+			wantState: map[string]int{"b": 1},
+		},
+		{
+			name:      "ERR: not found",
+			put:       tpp.Unexpected(),
+			del:       tpp.Unexpected(),
+			wantErr:   true,
+			wantState: map[string]int{},
+		},
+		{
+			name:      "ERR: get forced",
+			initial:   map[string]int{"a": 1},
+			get:       tpp.Err(),
+			put:       tpp.Unexpected(),
+			del:       tpp.Unexpected(),
+			wantErr:   true,
+			wantState: map[string]int{"a": 1},
+		},
+		{
+			name:      "ERR: delete forced",
+			initial:   map[string]int{"a": 1},
+			del:       tpp.Err(),
+			wantErr:   true,
+			wantState: map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name:      "Get returns forced value",
+			initial:   map[string]int{"a": 1},
+			get:       tpp.OK(5),
+			wantState: map[string]int{"b": 5},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := tpp.NewStatefulFake(tt.initial).NotFound(errNotFound)
+			repo := testdata.NewMockRepo[int](t)
+
+			tt.get.Expectorise(repo.EXPECT().Get(tpp.Arg(), tpp.Arg()), fake.Get())
+			tt.put.Expectorise(repo.EXPECT().Put(tpp.Arg(), tpp.Arg(), tpp.Arg()), fake.Put())
+			tt.del.Expectorise(repo.EXPECT().Delete(tpp.Arg(), tpp.Arg()), fake.Delete())
+
+			err := rename(context.Background(), repo, "a", "b")
+			require.Equal(t, tt.wantErr, err != nil, err)
+			require.Equal(t, tt.wantState, fake.State())
+		})
+	}
+
+	t.Run("Delete returns whether it was there", func(t *testing.T) {
+		fake := tpp.NewStatefulFake(map[string]int{"a": 1})
+		repo := testdata.NewMockRepo[int](t)
+		tpp.ExpectoriseMulti(nil, func() tpp.MockCall {
+			return repo.EXPECT().Delete(tpp.Arg(), tpp.Arg())
+		}, fake.Delete())
+
+		ok, err := repo.Delete(context.Background(), "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repo.Delete(context.Background(), "a")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("interface values", func(t *testing.T) {
+		fake := tpp.NewStatefulFake[string, any](nil)
+		repo := testdata.NewMockRepo[any](t)
+
+		var get, put tpp.Expect
+		get.Expectorise(repo.EXPECT().Get(tpp.Arg(), tpp.Arg()), fake.Get())
+		put.Expectorise(repo.EXPECT().Put(tpp.Arg(), tpp.Arg(), tpp.Arg()), fake.Put())
+
+		require.NoError(t, repo.Put(context.Background(), "a", nil))
+		require.NoError(t, repo.Put(context.Background(), "b", "foo"))
+		v, err := repo.Get(context.Background(), "b")
+		require.NoError(t, err)
+		require.Equal(t, "foo", v)
+		require.Equal(t, map[string]any{"a": nil, "b": "foo"}, fake.State())
+	})
+
+	t.Run("panics on wrong shape", func(t *testing.T) {
+		fake := tpp.NewStatefulFake[int, string](nil)
+		repo := testdata.NewMockRepo[int](_t())
+
+		var e tpp.Expect
+		require.PanicsWithError(t, "tpp: can't bind func(context.Context, string) (int, error) to StatefulFake.Get: no int arg", func() {
+			e.Expectorise(repo.EXPECT().Get(tpp.Arg(), tpp.Arg()), fake.Get())
+		})
+	})
+
+	t.Run("panics on bare testify mock", func(t *testing.T) {
+		fake := tpp.NewStatefulFake[string, int](nil)
+		call := (&testifymock.Mock{}).On("Get", tpp.Arg())
+
+		var e tpp.Expect
+		require.Panics(t, func() { e.Expectorise(call, fake.Get()) })
+	})
+}
@@ -0,0 +1,243 @@
+package tpp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// FuzzExpects returns a Fuzzer which derives test cases from fuzz input, so
+// that `go test -fuzz` can explore combinations of dependency failures. For
+// example:
+//
+//	func FuzzGetFoo(f *testing.F) {
+//		tpp.FuzzExpects(f, testCase{}).Fuzz(func(t *testing.T, tt testCase) {
+//			mock := NewMockFooGetter(t)
+//			tt.getFoo.Expectorise(mock.EXPECT().GetFoo())
+//
+//			// The subject mustn't panic, whatever its dependencies do.
+//			_, _ = subject.XXX(mock)
+//		})
+//	}
+//
+// For each fuzz input, every Expect field of the template is deterministically
+// set to one of OK, Err, Unexpected or Maybe. Any args given to the template's
+// Expects are kept. OK and Maybe Expects keep any return values given to the
+// template's Expects too, and return values for the rest which are generated
+// from the fuzz input once the mock's return types are known, by Expectorise.
+// The test case's other fields are copied from the template, and its name is
+// set by NameCase, as with Matrix.
+//
+// Seeds are added to the corpus for all the fields being OK, all Err, and so
+// on, so that the usual `go test` exercises these even without -fuzz.
+//
+// Unexpected Expects will fail the test if the subject calls the mock anyway,
+// so subjects which always call their dependencies will need to handle that,
+// e.g. by ignoring test cases with Unexpected fields. []Expect and typed Expect
+// fields are left as they are in the template.
+func FuzzExpects[T any](f *testing.F, template T) *Fuzzer[T] {
+	typ := reflect.TypeOf(template)
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tpp: FuzzExpects test case must be a struct, got %s", typ))
+	}
+
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Type == expectType {
+			fields = append(fields, i)
+		}
+	}
+
+	for kind := byte(0); kind < numFuzzKinds; kind++ {
+		seed := make([]byte, len(fields)*fuzzFieldLen)
+		for i := range fields {
+			seed[i*fuzzFieldLen] = kind
+		}
+		f.Add(seed)
+	}
+
+	return &Fuzzer[T]{f: f, template: template, fields: fields}
+}
+
+// Fuzzer runs a fuzz test over test cases derived from fuzz input. See
+// FuzzExpects.
+type Fuzzer[T any] struct {
+	f        *testing.F
+	template T
+	fields   []int
+}
+
+// Fuzz runs fn with a test case derived from each fuzz input, like
+// testing.F.Fuzz.
+func (z *Fuzzer[T]) Fuzz(fn func(t *testing.T, tt T)) {
+	z.f.Fuzz(func(t *testing.T, data []byte) {
+		tc := z.Case(data)
+		t.Log(NameCase(tc))
+		fn(t, tc)
+	})
+}
+
+// Case returns the test case derived from the given fuzz input. This is useful
+// for reproducing a failure outside of the fuzzer.
+func (z *Fuzzer[T]) Case(data []byte) T {
+	v := caseValue(z.template)
+
+	for _, i := range z.fields {
+		chunk := make([]byte, fuzzFieldLen)
+		data = data[copy(chunk, data):]
+
+		f := caseField(v, i)
+		e := f.Interface().(Expect)
+		f.Set(reflect.ValueOf(fuzzExpect(e, chunk, v.Type().Field(i).Name)))
+	}
+
+	if idx := caseNameField(v.Type()); idx >= 0 {
+		if name := caseField(v, idx); name.String() == "" {
+			name.SetString(NameCase(v.Interface()))
+		}
+	}
+
+	return v.Interface().(T)
+}
+
+const (
+	// numFuzzKinds is the number of kinds of Expect we choose between: OK,
+	// Err, Unexpected and Maybe.
+	numFuzzKinds = 4
+
+	// fuzzFieldLen is how many bytes of fuzz input each Expect field takes:
+	// one for its kind, and the rest for the seed for its return values.
+	fuzzFieldLen = 1 + 8
+)
+
+// fuzzExpect returns a copy of e with its behaviour chosen by the given chunk
+// of fuzz input, which is fuzzFieldLen bytes long. OK and Maybe keep e's
+// returns.
+func fuzzExpect(e Expect, chunk []byte, field string) Expect {
+	seed := int64(binary.LittleEndian.Uint64(chunk[1:]))
+
+	out := Expect{
+		argReplacements: e.argReplacements,
+		nTimes:          e.nTimes,
+		effects:         e.effects,
+	}
+
+	switch chunk[0] % numFuzzKinds {
+	case 0:
+		out.Expected = ptr(true)
+		out.Return = e.Return
+		out.fuzzSeed = &seed
+	case 1:
+		out.Expected = ptr(true)
+		out.Err = &expectError{site: "fuzzed " + field}
+	case 2:
+		out.Expected = ptr(false)
+	default:
+		out.Return = e.Return
+		out.fuzzSeed = &seed
+	}
+
+	return out
+}
+
+// fuzzReturns returns the return values of the mock call. The first are those
+// which were set, and the non-error values after them are generated from the
+// given seed. The error values are left nil.
+func fuzzReturns(layout *mockLayout, seed int64, set []any) []any {
+	r := rand.New(rand.NewSource(seed))
+
+	values := append([]any{}, set...)
+	for i := len(set); i < layout.returnType.NumIn(); i++ {
+		if layout.isErrSlot(i) {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, fuzzValue(r, layout.returnType.In(i), 0).Interface())
+	}
+	return values
+}
+
+// fuzzMaxDepth is how deeply fuzzValue fills in nested types. Anything deeper
+// is zero valued, so that recursive types terminate.
+const fuzzMaxDepth = 3
+
+// fuzzValue returns a value of the given type generated by r.
+//
+// Only exported struct fields are filled in, as the others may have invariants
+// which we'd break. Interfaces, funcs and chans are left nil, as we don't know
+// what to put in them.
+func fuzzValue(r *rand.Rand, typ reflect.Type, depth int) reflect.Value {
+	v := reflect.New(typ).Elem()
+	if depth > fuzzMaxDepth {
+		return v
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(fuzzInt(r))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(fuzzInt(r)))
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(fuzzInt(r)) * r.Float64())
+
+	case reflect.String:
+		b := make([]byte, r.Intn(8))
+		r.Read(b)
+		v.SetString(string(b))
+
+	case reflect.Ptr:
+		// Sometimes nil, as that's a favourite of panics.
+		if r.Intn(4) > 0 {
+			v.Set(fuzzValue(r, typ.Elem(), depth+1).Addr())
+		}
+
+	case reflect.Slice:
+		if n := r.Intn(4); n > 0 {
+			v.Set(reflect.MakeSlice(typ, n, n))
+			for i := 0; i < n; i++ {
+				v.Index(i).Set(fuzzValue(r, typ.Elem(), depth+1))
+			}
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(fuzzValue(r, typ.Elem(), depth+1))
+		}
+
+	case reflect.Map:
+		if n := r.Intn(4); n > 0 {
+			v.Set(reflect.MakeMapWithSize(typ, n))
+			for i := 0; i < n; i++ {
+				v.SetMapIndex(fuzzValue(r, typ.Key(), depth+1), fuzzValue(r, typ.Elem(), depth+1))
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).IsExported() {
+				v.Field(i).Set(fuzzValue(r, typ.Field(i).Type, depth+1))
+			}
+		}
+	}
+
+	return v
+}
+
+// fuzzInt returns a random int, favouring the edge cases.
+func fuzzInt(r *rand.Rand) int64 {
+	switch r.Intn(4) {
+	case 0:
+		return 0
+	case 1:
+		return -1
+	default:
+		return r.Int63n(1000)
+	}
+}
@@ -0,0 +1,102 @@
+package tpp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+type fuzzCase struct {
+	name    string
+	getFoo  tpp.Expect
+	saveFoo tpp.Expect
+	arg     int
+}
+
+// sumFoos is a subject which calls both its dependencies and sums the results.
+// It handles nil returns, so it shouldn't panic whatever they do.
+func sumFoos(getFoo testdata.StructyThing, saveFoo testdata.IntyThing, arg int) (int, error) {
+	s, err := getFoo.DoThing(context.Background(), &testdata.Struct{A: arg})
+	if err != nil {
+		return 0, err
+	}
+
+	var sum int
+	if s != nil {
+		sum = s.A + s.B
+	}
+
+	n, err := saveFoo.DoThing(arg, sum)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func FuzzSumFoos(f *testing.F) {
+	tpp.FuzzExpects(f, fuzzCase{arg: 1}).Fuzz(func(t *testing.T, tt fuzzCase) {
+		if isUnexpected(tt.getFoo) || (tt.getFoo.Err == nil && isUnexpected(tt.saveFoo)) {
+			t.Skip("sumFoos calls its dependencies unless getFoo fails")
+		}
+
+		getFoo := testdata.NewMockStructyThing(t)
+		tt.getFoo.Expectorise(getFoo.EXPECT().DoThing(context.Background(), &testdata.Struct{A: tt.arg}))
+
+		// saveFoo isn't called if getFoo fails.
+		saveFoo := testdata.NewMockIntyThing(_t())
+		tt.saveFoo.Expectorise(saveFoo.EXPECT().DoThing(tt.arg, tpp.Arg()))
+
+		_, err := sumFoos(getFoo, saveFoo, tt.arg)
+		if tt.getFoo.Err != nil {
+			tpp.RequireErrFrom(t, err, tt.getFoo)
+		}
+	})
+}
+
+func isUnexpected(e tpp.Expect) bool {
+	return e.Expected != nil && !*e.Expected
+}
+
+// pairCase is a test case whose template sets some of the args and returns.
+type pairCase struct {
+	name    string
+	getPair tpp.Expect
+	getFoo  tpp.Expect
+	arg     int
+}
+
+func FuzzExpectsTemplate(f *testing.F) {
+	template := pairCase{getPair: tpp.Given(3).OK(42), arg: 1}
+	tpp.FuzzExpects(f, template).Fuzz(func(t *testing.T, tt pairCase) {
+		require.Equal(t, 1, tt.arg, "keeps the template's other fields")
+		require.Regexp(t, `^getPair=Given\.(OK|Err|Unexpected|Maybe)/getFoo=(OK|Err|Unexpected|Maybe)$`, tt.name)
+
+		if !isUnexpected(tt.getPair) {
+			mock := testdata.NewMockPairyThing(t)
+			tt.getPair.Expectorise(mock.EXPECT().DoThing(tpp.Arg()))
+
+			// Only the template's args match.
+			n, _, err := mock.DoThing(3)
+			if tt.getPair.Err == nil {
+				require.NoError(t, err)
+				require.Equal(t, 42, n, "keeps the template's returns")
+			}
+		}
+
+		if !isUnexpected(tt.getFoo) {
+			var got []*testdata.Struct
+			for i := 0; i < 2; i++ {
+				mock := testdata.NewMockStructyThing(t)
+				tt.getFoo.Expectorise(mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+				s, _ := mock.DoThing(context.Background(), nil)
+				got = append(got, s)
+			}
+			require.Equal(t, got[0], got[1], "generates returns deterministically")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package tpp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+// These check that mocks of generic interfaces work end to end, i.e., that the
+// mock returns what we configured when it's called. The generic tests over
+// testStructures check how the calls are configured.
+
+func TestGenericMock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Zero value returns zero T", func(t *testing.T) {
+		ptrs := testdata.NewMockRepo[*testdata.Struct](t)
+		ints := testdata.NewMockRepo[int](t)
+		anys := testdata.NewMockRepo[any](t)
+
+		var e tpp.Expect
+		e.Expectorise(ptrs.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+		e.Expectorise(ints.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+		e.Expectorise(anys.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+
+		p, err := ptrs.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Nil(t, p)
+
+		i, err := ints.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Equal(t, 0, i)
+
+		a, err := anys.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Nil(t, a)
+	})
+
+	t.Run("OK() returns T", func(t *testing.T) {
+		mock := testdata.NewMockRepo[*testdata.Struct](t)
+		e := tpp.OK(&testdata.Struct{A: 1})
+		e.Expectorise(mock.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+
+		got, err := mock.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Equal(t, &testdata.Struct{A: 1}, got)
+	})
+
+	t.Run("OK() returns any", func(t *testing.T) {
+		mock := testdata.NewMockRepo[any](t)
+		e := tpp.OK("foo")
+		e.Expectorise(mock.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+
+		got, err := mock.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Equal(t, "foo", got)
+	})
+
+	t.Run("OK() panics for wrong T", func(t *testing.T) {
+		mock := testdata.NewMockRepo[int](_t())
+		e := tpp.OK("foo")
+		require.Panics(t, func() {
+			e.Expectorise(mock.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+		})
+	})
+
+	t.Run("Err() returns zero T", func(t *testing.T) {
+		mock := testdata.NewMockRepo[int](t)
+		e := tpp.Err()
+		e.Expectorise(mock.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+
+		got, err := mock.Get(ctx, "id")
+		require.Equal(t, e.Err, err)
+		require.Equal(t, 0, got)
+	})
+
+	t.Run("Given() matches T arg", func(t *testing.T) {
+		mock := testdata.NewMockRepo[testdata.Struct](t)
+		e := tpp.Given(ctx, "id", testdata.Struct{A: 1}).Return(nil)
+		e.Expectorise(mock.EXPECT().Put(tpp.Arg(), tpp.Arg(), tpp.Arg()))
+
+		require.NoError(t, mock.Put(ctx, "id", testdata.Struct{A: 1}))
+	})
+
+	t.Run("ExpectoriseMulti() returns in order", func(t *testing.T) {
+		mock := testdata.NewMockRepo[int](t)
+		tpp.ExpectoriseMulti(
+			[]tpp.Expect{tpp.OK(1).Once(), tpp.OK(2).Once()},
+			func() tpp.MockCall {
+				return mock.EXPECT().Get(tpp.Arg(), tpp.Arg())
+			},
+		)
+
+		got1, _ := mock.Get(ctx, "id")
+		got2, _ := mock.Get(ctx, "id")
+		require.Equal(t, []int{1, 2}, []int{got1, got2})
+	})
+
+	t.Run("Expect1[T] works", func(t *testing.T) {
+		mock := testdata.NewMockRepo[int](t)
+		tpp.OK1(42).Expectorise(mock.EXPECT().Get(tpp.Arg(), tpp.Arg()))
+
+		got, err := mock.Get(ctx, "id")
+		require.NoError(t, err)
+		require.Equal(t, 42, got)
+	})
+}
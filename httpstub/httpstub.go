@@ -0,0 +1,251 @@
+// Package httpstub provides an HTTP server to stand in for HTTP dependencies in
+// tpp tests, configured with tpp.Expects just like mocks are.
+//
+// For example:
+//
+//	func TestXXX(t *testing.T) {
+//		for _, tt := range []struct {
+//			name    string
+//			getUser tpp.Expect
+//			wantErr bool
+//		}{
+//			{
+//				name:    "OK",
+//				getUser: tpp.Return(http.StatusOK, User{Name: "foo"}, nil),
+//			},
+//			{
+//				name:    "ERR: getUser",
+//				getUser: tpp.Err(),
+//				wantErr: true,
+//			},
+//		} {
+//			t.Run(tt.name, func(t *testing.T) {
+//				srv := httpstub.New(t)
+//				tt.getUser.Expectorise(srv.On("GET /users/{id}"))
+//
+//				subject := subject.New(srv.URL)
+//				err := subject.XXX()
+//
+//				require.Equal(t, tt.wantErr, err != nil)
+//			})
+//		}
+//	}
+//
+// Each route is configured like a mock call which takes the request's query and
+// body, and returns the response's status and body, and an error:
+//
+//	func(query url.Values, body string) (status int, body any, err error)
+//
+// So tpp.Given() matches on the query and body, e.g.,
+//
+//	tpp.Given(url.Values{"q": {"foo"}}, mock.Anything).Return(http.StatusOK, "bar", nil)
+//
+// The query is never nil, so use url.Values{} to match a request without one.
+//
+// A zero status is taken to be 200 OK, so that zero value Expects respond OK.
+// The body returned is written as is if it's a string or []byte, and is JSON
+// encoded otherwise. An error (e.g., from tpp.Err()) results in a 500, or in
+// the connection being reset with ResetOnErr().
+//
+// Requests which aren't expected fail the test, as they would for a mock.
+package httpstub
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	testifymock "github.com/stretchr/testify/mock"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/internal/stubt"
+)
+
+// Server is an HTTP server whose routes are configured with tpp.Expects.
+//
+// It embeds the *httptest.Server, so its URL and Client are available as usual.
+// It's closed, and its expectations asserted, when the test finishes.
+type Server struct {
+	*httptest.Server
+
+	mock testifymock.Mock
+	t    TestingT
+	opts options
+
+	mu     sync.Mutex
+	routes []route
+}
+
+// TestingT is the subset of testing.T which the Server needs.
+type TestingT interface {
+	testifymock.TestingT
+	Cleanup(func())
+}
+
+// Option configures a Server.
+type Option func(*options)
+
+type options struct {
+	resetOnErr bool
+}
+
+// ResetOnErr makes routes which return an error reset the connection, rather
+// than respond with a 500. This is useful for testing how the subject handles
+// network failures.
+func ResetOnErr() Option {
+	return func(opt *options) {
+		opt.resetOnErr = true
+	}
+}
+
+// New starts a new Server, which is closed when the test finishes.
+func New(t TestingT, options ...Option) *Server {
+	s := &Server{t: t}
+	for _, o := range options {
+		o(&s.opts)
+	}
+
+	// The handler runs on the server's goroutines, where FailNow can't be
+	// called. See stubt.T.
+	s.mock.Test(stubt.T{TestingT: t})
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	t.Cleanup(func() { s.mock.AssertExpectations(t) })
+
+	return s
+}
+
+// On returns a call for the given route, which should be Expectorised. Its args
+// are tpp.Arg()s, to be filled in by tpp.Given().
+//
+// The route is a method and a path pattern, e.g., "GET /users/{id}". Segments
+// in braces match any one segment, and a final "*" matches the rest of the
+// path. Routes are matched in the order they're first registered.
+func (s *Server) On(route string) *Call {
+	r, err := parseRoute(route)
+	if err != nil {
+		panic(err)
+	}
+	s.register(r)
+
+	return &Call{Call: s.mock.On(route, tpp.Arg(), tpp.Arg())}
+}
+
+// register registers the route, if it isn't already. Routes may be registered
+// while the server's goroutines are matching requests against them.
+func (s *Server) register(r route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.routes {
+		if existing.key == r.key {
+			return
+		}
+	}
+	s.routes = append(s.routes, r)
+}
+
+// Call is a call for a route, which is configured like a mockery mock call.
+type Call struct {
+	*testifymock.Call
+}
+
+// Run sets a handler to be called with the query and body of each request to
+// the route, before responding.
+func (c *Call) Run(run func(query url.Values, body string)) *Call {
+	c.Call.Run(func(args testifymock.Arguments) {
+		run(args[0].(url.Values), args[1].(string))
+	})
+	return c
+}
+
+// Return sets the response for the route. See the package docs.
+func (c *Call) Return(status int, body any, err error) *Call {
+	c.Call.Return(status, body, err)
+	return c
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.match(r)
+	if !ok {
+		s.t.Errorf("httpstub: unexpected request: %s %s", r.Method, r.URL)
+		http.Error(w, "httpstub: unexpected request", http.StatusNotFound)
+		return
+	}
+
+	body := new(strings.Builder)
+	if r.Body != nil {
+		if _, err := copyBody(body, r); err != nil {
+			s.t.Errorf("httpstub: reading request body: %v", err)
+		}
+	}
+
+	ret, ok := stubt.Called(&s.mock, key, r.URL.Query(), body.String())
+	if !ok {
+		http.Error(w, "httpstub: unexpected request", http.StatusInternalServerError)
+		return
+	}
+
+	if err := ret.Error(2); err != nil {
+		if s.opts.resetOnErr {
+			reset(w)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.respond(w, ret.Int(0), ret.Get(1))
+}
+
+func (s *Server) respond(w http.ResponseWriter, status int, body any) {
+	b, contentType, err := stubt.Body(body)
+	if err != nil {
+		s.t.Errorf("httpstub: encoding response body: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	w.WriteHeader(stubt.Status(status))
+	_, _ = w.Write(b)
+}
+
+// match returns the key of the first route which matches the request.
+func (s *Server) match(r *http.Request) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, route := range s.routes {
+		if route.matches(r.Method, r.URL.Path) {
+			return route.key, true
+		}
+	}
+	return "", false
+}
+
+// reset closes the connection without a response, and such that the client
+// sees it reset rather than closed cleanly.
+func reset(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic("httpstub: can't reset connection: ResponseWriter is not a Hijacker")
+	}
+
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		panic(fmt.Sprintf("httpstub: can't reset connection: %v", err))
+	}
+
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,253 @@
+package httpstub_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/httpstub"
+)
+
+// We use this dummy testing.T to pass into the code under test, where we want
+// the server to fail the test, since failing *our* test would be wrong.
+var _t = func() *testing.T {
+	return &testing.T{}
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func TestServer(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		getUser    tpp.Expect
+		options    []httpstub.Option
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantErr    bool
+	}{
+		{
+			name:       "OK: JSON body",
+			getUser:    tpp.Return(http.StatusOK, user{Name: "foo"}, nil),
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"foo"}`,
+		},
+		{
+			name:       "OK: string body",
+			getUser:    tpp.OK(http.StatusCreated, "created"),
+			path:       "/users/1",
+			wantStatus: http.StatusCreated,
+			wantBody:   "created",
+		},
+		{
+			name:       "OK: []byte body",
+			getUser:    tpp.OK(http.StatusOK, []byte("bytes")),
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+			wantBody:   "bytes",
+		},
+		{
+			name:       "OK: zero value",
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OK: Given() query",
+			getUser:    tpp.Given(url.Values{"q": {"x"}}, "").Return(http.StatusOK, "x", nil),
+			path:       "/users/1?q=x",
+			wantStatus: http.StatusOK,
+			wantBody:   "x",
+		},
+		{
+			name:       "OK: Given() body",
+			getUser:    tpp.Given(url.Values{}, "hello").Return(http.StatusOK, "hi", nil),
+			path:       "/users/1",
+			body:       "hello",
+			wantStatus: http.StatusOK,
+			wantBody:   "hi",
+		},
+		{
+			name:       "OK: Given() matcher",
+			getUser:    tpp.Given(testifymock.Anything, testifymock.Anything).Return(http.StatusOK, "hi", nil),
+			path:       "/users/1?q=y",
+			body:       "anything",
+			wantStatus: http.StatusOK,
+			wantBody:   "hi",
+		},
+		{
+			name:       "ERR: Err() gives 500",
+			getUser:    tpp.Err(),
+			path:       "/users/1",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "ERR: Err() resets connection",
+			getUser: tpp.Err(),
+			options: []httpstub.Option{httpstub.ResetOnErr()},
+			path:    "/users/1",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httpstub.New(t, tt.options...)
+			tt.getUser.Expectorise(srv.On("POST /users/{id}"))
+
+			resp, err := http.Post(srv.URL+tt.path, "text/plain", strings.NewReader(tt.body))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, string(b))
+			}
+		})
+	}
+}
+
+func TestServerFails(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		getUser tpp.Expect
+		path    string
+		hits    int
+	}{
+		{
+			name:    "Unexpected() route is hit",
+			getUser: tpp.Unexpected(),
+			path:    "/users/1",
+		},
+		{
+			name:    "Given() doesn't match",
+			getUser: tpp.Given(url.Values{"q": {"x"}}, "").Return(http.StatusOK, "x", nil),
+			path:    "/users/1?q=y",
+		},
+		{
+			name:    "Times() exceeded",
+			getUser: tpp.OK(http.StatusOK, "").Once(),
+			path:    "/users/1",
+			hits:    2,
+		},
+		{
+			name: "no route",
+			path: "/other",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := _t()
+			srv := httpstub.New(ft)
+			tt.getUser.Expectorise(srv.On("GET /users/{id}"))
+
+			var resp *http.Response
+			for i := 0; i < max(tt.hits, 1); i++ {
+				var err error
+				resp, err = http.Get(srv.URL + tt.path)
+				require.NoError(t, err)
+				resp.Body.Close()
+			}
+
+			require.True(t, ft.Failed())
+			require.GreaterOrEqual(t, resp.StatusCode, 400)
+		})
+	}
+}
+
+func TestServerTimes(t *testing.T) {
+	srv := httpstub.New(t)
+	e := tpp.OK(http.StatusOK, "").Times(2)
+	e.Expectorise(srv.On("GET /users/{id}"))
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(srv.URL + "/users/1")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+}
+
+func TestServerOnWhileServing(t *testing.T) {
+	srv := httpstub.New(t)
+	e := tpp.OK(http.StatusOK, "")
+	e.Expectorise(srv.On("GET /users/{id}"))
+
+	// Routes are added while the server's goroutines are matching requests
+	// against them. This is most useful with -race, as it's run in CI.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			resp, err := http.Get(srv.URL + "/users/1")
+			if err != nil {
+				t.Errorf("GET: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		srv.On(fmt.Sprintf("GET /posts/%d", i)).Maybe()
+	}
+	<-done
+}
+
+func TestRoutes(t *testing.T) {
+	for _, tt := range []struct {
+		route string
+		path  string
+		want  bool
+	}{
+		{route: "GET /users", path: "/users", want: true},
+		{route: "GET /users", path: "/users/", want: true},
+		{route: "GET /users", path: "/users/1", want: false},
+		{route: "GET /users/{id}", path: "/users/1", want: true},
+		{route: "GET /users/{id}", path: "/users", want: false},
+		{route: "GET /users/{id}/posts", path: "/users/1/posts", want: true},
+		{route: "GET /users/{id}/posts", path: "/users/1/likes", want: false},
+		{route: "GET /files/*", path: "/files/a/b/c", want: true},
+		{route: "GET /files/*", path: "/other/a", want: false},
+		{route: "GET /", path: "/", want: true},
+	} {
+		t.Run(tt.route+" "+tt.path, func(t *testing.T) {
+			ft := _t()
+			srv := httpstub.New(ft)
+			var e tpp.Expect
+			e.Expectorise(srv.On(tt.route))
+
+			resp, err := http.Get(srv.URL + tt.path)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			require.Equal(t, tt.want, !ft.Failed())
+		})
+	}
+
+	t.Run("invalid route panics", func(t *testing.T) {
+		srv := httpstub.New(_t())
+		require.Panics(t, func() { srv.On("/users") })
+		require.Panics(t, func() { srv.On("GET /files/*/foo") })
+	})
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
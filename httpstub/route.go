@@ -0,0 +1,77 @@
+package httpstub
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// route is a parsed route, such as "GET /users/{id}".
+type route struct {
+	key      string
+	method   string
+	segments []string
+}
+
+func parseRoute(key string) (route, error) {
+	method, path, ok := strings.Cut(key, " ")
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		return route{}, fmt.Errorf("httpstub: route must be \"METHOD /path\", got %q", key)
+	}
+
+	segments := splitPath(path)
+	for i, seg := range segments {
+		if seg == "*" && i != len(segments)-1 {
+			return route{}, fmt.Errorf("httpstub: * must be the last segment of route %q", key)
+		}
+	}
+
+	return route{
+		key:      key,
+		method:   method,
+		segments: segments,
+	}, nil
+}
+
+// matches returns whether the route matches the given method and path.
+func (r route) matches(method, path string) bool {
+	if r.method != method {
+		return false
+	}
+
+	segments := splitPath(path)
+	for i, seg := range r.segments {
+		if seg == "*" {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if isParam(seg) {
+			continue
+		}
+		if seg != segments[i] {
+			return false
+		}
+	}
+
+	return len(segments) == len(r.segments)
+}
+
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func copyBody(w io.Writer, r *http.Request) (int64, error) {
+	defer r.Body.Close()
+	return io.Copy(w, r.Body)
+}
@@ -0,0 +1,70 @@
+// Package stubt has the helpers shared by tpp's stand-ins for dependencies,
+// such as httpstub, which are testify mocks called from goroutines other than
+// the test's.
+package stubt
+
+import (
+	"encoding/json"
+	"net/http"
+
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// T wraps a test's TestingT for use by a mock from goroutines other than the
+// test's. FailNow must only be called from the test's goroutine, so we panic
+// instead, and Called recovers. The test still fails, since Errorf is always
+// called first.
+type T struct {
+	testifymock.TestingT
+}
+
+type failNow struct{}
+
+func (T) FailNow() {
+	panic(failNow{})
+}
+
+// Called calls the mock's method with the args, returning ok=false if it failed
+// the test because the call wasn't expected. The mock's test must be a T.
+func Called(m *testifymock.Mock, method string, args ...any) (ret testifymock.Arguments, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isFailNow := r.(failNow); !isFailNow {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+
+	return m.MethodCalled(method, args...), true
+}
+
+// Status returns the status to respond with, given the status returned by an
+// Expect. The zero value Expect returns a zero status, which we take to mean
+// 200 OK.
+func Status(status int) int {
+	if status == 0 {
+		return http.StatusOK
+	}
+	return status
+}
+
+// Body returns the body to respond with, given the body returned by an Expect,
+// and its content type if it's set by the encoding. Strings and []byte are used
+// as they are, and anything else is JSON encoded.
+func Body(body any) (b []byte, contentType string, err error) {
+	switch v := body.(type) {
+	case nil:
+		return nil, "", nil
+	case string:
+		return []byte(v), "", nil
+	case []byte:
+		return v, "", nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, "", err
+		}
+		return b, "application/json", nil
+	}
+}
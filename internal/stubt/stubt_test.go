@@ -0,0 +1,79 @@
+package stubt
+
+import (
+	"net/http"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordT is a TestingT which records whether the test failed.
+type recordT struct {
+	failed bool
+}
+
+func (r *recordT) Logf(string, ...any)   {}
+func (r *recordT) Errorf(string, ...any) { r.failed = true }
+func (r *recordT) FailNow()              { r.failed = true }
+
+func TestCalled(t *testing.T) {
+	t.Run("returns the call's returns", func(t *testing.T) {
+		var m testifymock.Mock
+		m.Test(T{&recordT{}})
+		m.On("Foo", 1).Return("bar")
+
+		ret, ok := Called(&m, "Foo", 1)
+		require.True(t, ok)
+		require.Equal(t, "bar", ret.String(0))
+	})
+
+	t.Run("fails unexpected call without FailNow", func(t *testing.T) {
+		rt := &recordT{}
+		var m testifymock.Mock
+		m.Test(T{rt})
+
+		_, ok := Called(&m, "Foo", 1)
+		require.False(t, ok)
+		require.True(t, rt.failed)
+	})
+
+	t.Run("passes on other panics", func(t *testing.T) {
+		var m testifymock.Mock
+		m.Test(T{&recordT{}})
+		m.On("Foo").Run(func(testifymock.Arguments) { panic("boom") })
+
+		require.PanicsWithValue(t, "boom", func() { Called(&m, "Foo") })
+	})
+}
+
+func TestBody(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		body            any
+		want            string
+		wantContentType string
+	}{
+		{name: "nil", body: nil, want: ""},
+		{name: "string", body: "foo", want: "foo"},
+		{name: "bytes", body: []byte("foo"), want: "foo"},
+		{name: "JSON", body: map[string]int{"a": 1}, want: `{"a":1}`, wantContentType: "application/json"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, contentType, err := Body(tt.body)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(b))
+			require.Equal(t, tt.wantContentType, contentType)
+		})
+	}
+
+	t.Run("JSON error", func(t *testing.T) {
+		_, _, err := Body(func() {})
+		require.Error(t, err)
+	})
+}
+
+func TestStatus(t *testing.T) {
+	require.Equal(t, http.StatusOK, Status(0))
+	require.Equal(t, http.StatusTeapot, Status(http.StatusTeapot))
+}
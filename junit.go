@@ -0,0 +1,269 @@
+package tpp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// junitSuite is the root element of a JUnit report.
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties"`
+	Failure    *junitFailure    `xml:"failure"`
+	Skipped    *struct{}        `xml:"skipped"`
+}
+
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func writeJUnitReport(buf *bytes.Buffer, r *Report) error {
+	suite := junitSuite{Name: r.t.Name(), Tests: len(r.rows)}
+
+	var total float64
+	for _, row := range r.rows {
+		total += row.duration.Seconds()
+
+		tc := junitCase{
+			Name:      row.name,
+			ClassName: r.t.Name(),
+			Time:      fmt.Sprintf("%.3f", row.duration.Seconds()),
+		}
+		if len(row.expects) > 0 {
+			tc.Properties = &junitProperties{}
+		}
+		for _, ce := range row.expects {
+			tc.Properties.Property = append(tc.Properties.Property, junitProperty{Name: ce.field, Value: ce.desc})
+		}
+
+		switch row.outcome {
+		case "FAIL":
+			suite.Failures++
+			tc.Failure = junitFailureFor(row.expectations)
+		case "SKIP":
+			suite.Skipped++
+			tc.Skipped = &struct{}{}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	return nil
+}
+
+// junitFailureFor returns the failure of a case with the given Expectations,
+// listing the mock calls which may be to blame.
+func junitFailureFor(expectations []*Expectation) *junitFailure {
+	if len(expectations) == 0 {
+		return &junitFailure{
+			Message: "failed with no mock calls recorded",
+			Body:    "Pass tpp.WithTest(t) to Expectorise to record the mock calls which may be to blame, or see the test log.",
+		}
+	}
+
+	suspects := suspectCalls(expectations)
+	if len(suspects) == 0 {
+		return &junitFailure{
+			Message: "failed with all expected mock calls made",
+			Body:    "No Expects were unmet or Unexpected, so see the test log.",
+		}
+	}
+
+	return &junitFailure{
+		Message: fmt.Sprintf("failed with %d suspect mock call(s)", len(suspects)),
+		Body:    strings.Join(suspects, "\n"),
+	}
+}
+
+// suspectCalls returns descriptions of the mock calls which may be to blame for
+// a failed case: those which were expected but not made as many times as they
+// needed to be, and those which were Unexpected, with where they were set up.
+func suspectCalls(expectations []*Expectation) []string {
+	var suspects []string
+	for _, x := range expectations {
+		e := x.expect
+		switch {
+		case e.Expected != nil && !*e.Expected:
+			method := "mock call"
+			if x.call != nil {
+				method = x.call.Method
+			}
+			suspects = append(suspects, fmt.Sprintf("%s: Unexpected, so any call fails (set up at %s)", method, x.site))
+		case e.Expected != nil && x.call != nil:
+			if made, ok := x.callsMade(); !ok {
+				suspects = append(suspects, fmt.Sprintf(
+					"%s%v: unmet, %s (set up at %s)",
+					x.call.Method, x.call.Arguments, made, x.site,
+				))
+			}
+		}
+	}
+	return suspects
+}
+
+// junitCases holds the Expectations configured for each case of a JUnit
+// Report, keyed by the name of the case's test. Report.Case watches the case,
+// and Expectorise records each Expectation against the case whose test, or one
+// of its subtests, it was given by WithTest.
+type junitCases struct {
+	mu     sync.Mutex
+	byTest map[string][]*Expectation
+}
+
+func newJUnitCases() *junitCases {
+	return &junitCases{byTest: make(map[string][]*Expectation)}
+}
+
+// watch starts recording the Expectations for the named test.
+func (c *junitCases) watch(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byTest[name] = nil
+}
+
+// record records the Expectation against the case of the named test, or of its
+// closest parent test, if there is one being watched.
+func (c *junitCases) record(name string, x *Expectation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name != "" {
+		if xs, ok := c.byTest[name]; ok {
+			c.byTest[name] = append(xs, x)
+			return
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return
+		}
+		name = name[:i]
+	}
+}
+
+// take stops recording the Expectations for the named test, and returns those
+// which were recorded.
+func (c *junitCases) take(name string) []*Expectation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	xs := c.byTest[name]
+	delete(c.byTest, name)
+	return xs
+}
+
+// junitReports holds the cases of each JUnit Report being written, so that
+// Expectorise can record its Expectation in all of them. n is how many there
+// are, which can be checked without taking the lock.
+var junitReports = struct {
+	mu    sync.Mutex
+	cases map[*junitCases]struct{}
+	n     int32
+}{cases: make(map[*junitCases]struct{})}
+
+// addJUnitReport starts recording Expectations in the given cases.
+func addJUnitReport(c *junitCases) {
+	junitReports.mu.Lock()
+	defer junitReports.mu.Unlock()
+	junitReports.cases[c] = struct{}{}
+	atomic.StoreInt32(&junitReports.n, int32(len(junitReports.cases)))
+}
+
+// removeJUnitReport stops recording Expectations in the given cases.
+func removeJUnitReport(c *junitCases) {
+	junitReports.mu.Lock()
+	defer junitReports.mu.Unlock()
+	delete(junitReports.cases, c)
+	atomic.StoreInt32(&junitReports.n, int32(len(junitReports.cases)))
+}
+
+// recordJUnitExpectation records the Expectation for the named test in the
+// cases of each JUnit Report being written.
+func recordJUnitExpectation(name string, x *Expectation) {
+	junitReports.mu.Lock()
+	defer junitReports.mu.Unlock()
+	for c := range junitReports.cases {
+		c.record(name, x)
+	}
+}
+
+// junitActive returns whether any JUnit Reports are being written. Expectorise
+// only records where each Expectation was set up, for suspectCalls, while there
+// are any, since finding the caller is too slow to do for every one.
+func junitActive() bool {
+	return atomic.LoadInt32(&junitReports.n) > 0
+}
+
+// tppPkg is the import path of this package.
+var tppPkg = reflect.TypeOf(Expect{}).PkgPath()
+
+// callerSite returns the file and line of the closest caller outside of this
+// package and its subpackages, e.g., the meta-test which called Expectorise.
+func callerSite() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !inTppPkg(frame.Function) {
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// inTppPkg returns whether the fully qualified function name is in this package
+// or one of its subpackages, but not their tests.
+func inTppPkg(fn string) bool {
+	rest := strings.TrimPrefix(fn, tppPkg)
+	if rest == fn {
+		return false
+	}
+	if !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "/") {
+		return false
+	}
+
+	// Strip any subpackage path, so we're left with "pkg.Func" or ".Func".
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		rest = rest[i:]
+	}
+	pkg, _, _ := strings.Cut(rest, ".")
+	return !strings.HasSuffix(pkg, "_test")
+}
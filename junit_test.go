@@ -0,0 +1,164 @@
+package tpp
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp/testdata"
+)
+
+// withJUnitReport makes Expectorise act as if a JUnit Report is being written
+// for the rest of the test, and returns its cases.
+func withJUnitReport(t *testing.T) *junitCases {
+	c := newJUnitCases()
+	addJUnitReport(c)
+	t.Cleanup(func() { removeJUnitReport(c) })
+	return c
+}
+
+func TestJUnitReport(t *testing.T) {
+	_t := &testing.T{} // dummy testing.T for passing into code under test
+	cases := withJUnitReport(t)
+
+	type testCase struct {
+		getFoo  Expect
+		saveFoo Expect
+		delFoos []Expect
+	}
+
+	tc := testCase{
+		getFoo:  OK(1),
+		saveFoo: Unexpected(),
+		delFoos: []Expect{OK(1).Once(), OK(2).Once()},
+	}
+
+	var expectations []*Expectation
+	t.Run("failed", func(t *testing.T) {
+		cases.watch(t.Name())
+
+		mock := testdata.NewMockIntyThing(_t)
+		tc.getFoo.Expectorise(mock.EXPECT().DoThing(1, 2), WithTest(t))
+		tc.saveFoo.Expectorise(mock.EXPECT().DoThing(3, 4), WithTest(t))
+		ExpectoriseMulti(tc.delFoos, func() MockCall {
+			return mock.EXPECT().DoThing(5, 6)
+		}, WithTest(t))
+
+		_, _ = mock.DoThing(1, 2)
+		_, _ = mock.DoThing(5, 6)
+
+		expectations = cases.take(t.Name())
+	})
+
+	r := &Report{t: t, rows: []reportRow{
+		{name: "passed", expects: caseExpects(caseValue(testCase{})), outcome: "PASS"},
+		{name: "failed", expects: caseExpects(caseValue(tc)), expectations: expectations, outcome: "FAIL"},
+		{name: "failed: nothing recorded", outcome: "FAIL"},
+		{name: "skipped", outcome: "SKIP"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJUnitReport(&buf, r))
+
+	// This test is in package tpp, so the sites are outside of it, in package
+	// testing. See TestCallerSite.
+	got := regexp.MustCompile(`testing\.go:\d+`).ReplaceAllString(buf.String(), "testing.go:N")
+
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="TestJUnitReport" tests="4" failures="2" skipped="1" time="0.000">
+  <testcase name="passed" classname="TestJUnitReport" time="0.000">
+    <properties>
+      <property name="getFoo" value="Maybe"></property>
+      <property name="saveFoo" value="Maybe"></property>
+      <property name="delFoos" value="Maybe"></property>
+    </properties>
+  </testcase>
+  <testcase name="failed" classname="TestJUnitReport" time="0.000">
+    <properties>
+      <property name="getFoo" value="OK"></property>
+      <property name="saveFoo" value="Unexpected"></property>
+      <property name="delFoos" value="[OK.Times(1),OK.Times(1)]"></property>
+    </properties>
+    <failure message="failed with 2 suspect mock call(s)">DoThing: Unexpected, so any call fails (set up at testing.go:N)&#xA;DoThing[5 6]: unmet, 1 more time(s) needed (set up at testing.go:N)</failure>
+  </testcase>
+  <testcase name="failed: nothing recorded" classname="TestJUnitReport" time="0.000">
+    <failure message="failed with no mock calls recorded">Pass tpp.WithTest(t) to Expectorise to record the mock calls which may be to blame, or see the test log.</failure>
+  </testcase>
+  <testcase name="skipped" classname="TestJUnitReport" time="0.000">
+    <skipped></skipped>
+  </testcase>
+</testsuite>
+`, got)
+}
+
+func TestSiteOnlyRecordedForJUnit(t *testing.T) {
+	mock := testdata.NewMockIntyThing(&testing.T{})
+
+	e := OK(1)
+	require.Empty(t, e.Expectorise(mock.EXPECT().DoThing(1, 2)).site)
+
+	withJUnitReport(t)
+	require.NotEmpty(t, e.Expectorise(mock.EXPECT().DoThing(1, 2)).site)
+}
+
+func TestJUnitCases(t *testing.T) {
+	cases := withJUnitReport(t)
+	mock := testdata.NewMockIntyThing(&testing.T{})
+	e := OK(1)
+
+	t.Run("records against the case", func(t *testing.T) {
+		cases.watch(t.Name())
+
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2), WithTest(t))
+		require.Equal(t, []*Expectation{x}, cases.take(t.Name()))
+	})
+
+	t.Run("records subtests against the case", func(t *testing.T) {
+		cases.watch(t.Name())
+
+		var x *Expectation
+		t.Run("subtest", func(t *testing.T) {
+			x = e.Expectorise(mock.EXPECT().DoThing(1, 2), WithTest(t))
+		})
+		require.Equal(t, []*Expectation{x}, cases.take(t.Name()))
+	})
+
+	t.Run("ignores tests which aren't cases", func(t *testing.T) {
+		e.Expectorise(mock.EXPECT().DoThing(1, 2), WithTest(t))
+
+		cases.mu.Lock()
+		defer cases.mu.Unlock()
+		require.NotContains(t, cases.byTest, t.Name())
+	})
+
+	t.Run("records in each report", func(t *testing.T) {
+		other := withJUnitReport(t)
+		cases.watch(t.Name())
+		other.watch(t.Name())
+
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2), WithTest(t))
+		require.Equal(t, []*Expectation{x}, other.take(t.Name()))
+		require.Equal(t, []*Expectation{x}, cases.take(t.Name()))
+	})
+}
+
+func TestCallerSite(t *testing.T) {
+	for _, tt := range []struct {
+		fn   string
+		want bool
+	}{
+		{fn: tppPkg + ".(*Expect).Expectorise", want: true},
+		{fn: tppPkg + ".ExpectoriseMulti.func1", want: true},
+		{fn: tppPkg + "/httpstub.(*Call).Return", want: true},
+		{fn: tppPkg + "_test.TestXXX", want: false},
+		{fn: tppPkg + "/httpstub_test.TestXXX.func1", want: false},
+		{fn: tppPkg + "plus.Foo", want: false},
+		{fn: "main.main", want: false},
+	} {
+		t.Run(tt.fn, func(t *testing.T) {
+			require.Equal(t, tt.want, inTppPkg(tt.fn))
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package tpp
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// mockLayout is everything we need to know about a type of mock call which we
+// have to find out by reflection. This is the same for every call of the same
+// type, so we work it out once and cache it in layouts.
+//
+// As with the rest of reflect.go, this only deals with exported fields and
+// methods of the mock call.
+type mockLayout struct {
+	// argsIndex is the index of the Arguments field, for FieldByIndex.
+	argsIndex []int
+
+	// callIndex is the index of the embedded *mock.Call, for FieldByIndex. It's
+	// nil if the mock call is a bare *mock.Call, or if it has no *mock.Call.
+	callIndex []int
+
+	// returnIndex is the index of the Return method, for Method, and returnType
+	// is its type (without the receiver).
+	returnIndex int
+	returnType  reflect.Type
+
+	// errSlots are the indexes of returnType's args which are errors.
+	errSlots []int
+
+	// runType is the type of the func taken by the Run method, or nil if there
+	// isn't one that we understand. For bare testify calls, this is
+	// func(mock.Arguments).
+	runType reflect.Type
+
+	// runAndReturnIndex is the index of the RunAndReturn method, for Method, or
+	// -1 if there isn't one. Bare testify calls don't have it.
+	runAndReturnIndex int
+	runAndReturnType  reflect.Type
+}
+
+// layouts caches the *mockLayout for each reflect.Type of mock call.
+var layouts sync.Map
+
+// layoutOf returns the mockLayout for the given type of mock call.
+func layoutOf(t reflect.Type) (*mockLayout, error) {
+	if l, ok := layouts.Load(t); ok {
+		return l.(*mockLayout), nil
+	}
+
+	l, err := buildMockLayout(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// If another goroutine got here first, use theirs. They're the same anyway.
+	actual, _ := layouts.LoadOrStore(t, l)
+	return actual.(*mockLayout), nil
+}
+
+// buildMockLayout works out the mockLayout for the given type of mock call.
+func buildMockLayout(t reflect.Type) (*mockLayout, error) {
+	structType := t
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return nil, errors.New("mock must be struct")
+	}
+
+	// Arguments
+	args, ok := structType.FieldByName("Arguments")
+	if !ok || args.PkgPath != "" {
+		return nil, errors.New("args must be valid")
+	}
+
+	if args.Type.Kind() != reflect.Slice {
+		return nil, errors.New("args must be slice")
+	}
+
+	// The *mock.Call, which mockery embeds as Call.
+	var callIndex []int
+	if call, ok := structType.FieldByName("Call"); ok && call.PkgPath == "" && call.Type == callType {
+		callIndex = call.Index
+	}
+
+	// Return
+	ret, ok := t.MethodByName("Return")
+	if !ok {
+		return nil, errors.New("given mock has no Return method")
+	}
+
+	l := &mockLayout{
+		argsIndex:         args.Index,
+		callIndex:         callIndex,
+		returnIndex:       ret.Index,
+		returnType:        methodType(ret),
+		runAndReturnIndex: -1,
+	}
+
+	for i := 0; i < l.returnType.NumIn(); i++ {
+		if isErrorType(l.returnType.In(i)) {
+			l.errSlots = append(l.errSlots, i)
+		}
+	}
+
+	// Run and RunAndReturn, which are optional.
+	if run, ok := t.MethodByName("Run"); ok {
+		if typ := methodType(run); typ.NumIn() == 1 && typ.In(0).Kind() == reflect.Func {
+			l.runType = typ.In(0)
+		}
+	}
+
+	if rar, ok := t.MethodByName("RunAndReturn"); ok {
+		l.runAndReturnIndex = rar.Index
+		l.runAndReturnType = methodType(rar)
+	}
+
+	return l, nil
+}
+
+// errSlot returns the index of the return value which should hold retErr. This
+// is errAt if it's non-negative, and otherwise the last return value which is
+// an error that retErr can be assigned to. If there is none, -1 is returned.
+func (l *mockLayout) errSlot(retErr error, errAt int) int {
+	if errAt >= 0 {
+		return errAt
+	}
+
+	for i := len(l.errSlots) - 1; i >= 0; i-- {
+		slot := l.errSlots[i]
+		if reflect.TypeOf(retErr).AssignableTo(l.returnType.In(slot)) {
+			return slot
+		}
+	}
+
+	return -1
+}
+
+// isErrSlot returns whether the return value at index i is an error.
+func (l *mockLayout) isErrSlot(i int) bool {
+	for _, s := range l.errSlots {
+		if s == i {
+			return true
+		}
+	}
+	return false
+}
+
+var callType = reflect.TypeOf(&testifymock.Call{})
+
+// methodType returns the type of the method without its receiver, i.e., the
+// type of the method value you'd get from reflect.Value.Method.
+func methodType(m reflect.Method) reflect.Type {
+	in := make([]reflect.Type, m.Type.NumIn()-1)
+	for i := range in {
+		in[i] = m.Type.In(i + 1)
+	}
+
+	out := make([]reflect.Type, m.Type.NumOut())
+	for i := range out {
+		out[i] = m.Type.Out(i)
+	}
+
+	return reflect.FuncOf(in, out, m.Type.IsVariadic())
+}
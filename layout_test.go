@@ -0,0 +1,70 @@
+package tpp
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestLayoutOf(t *testing.T) {
+	_t := &testing.T{} // dummy testing.T for passing into code under test
+
+	t.Run("mockery call", func(t *testing.T) {
+		c := testdata.NewMockMultiErrorThing(_t).EXPECT().DoThing(1)
+		l, err := layoutOf(reflect.TypeOf(c))
+		require.NoError(t, err)
+
+		require.Equal(t, reflect.TypeOf(c.Return).String(), l.returnType.String())
+		require.Equal(t, []int{1, 2}, l.errSlots)
+		require.NotNil(t, l.runType)
+		require.NotEqual(t, -1, l.runAndReturnIndex)
+	})
+
+	t.Run("testify call", func(t *testing.T) {
+		c := (&mock.Mock{}).On("Test")
+		l, err := layoutOf(reflect.TypeOf(c))
+		require.NoError(t, err)
+
+		require.True(t, isVariadicAnyReturn(l.returnType))
+		require.Empty(t, l.errSlots)
+		require.Equal(t, -1, l.runAndReturnIndex)
+	})
+
+	t.Run("custom error types", func(t *testing.T) {
+		c := testdata.NewMockCodedErrorThing(_t).EXPECT().DoThing(1)
+		l, err := layoutOf(reflect.TypeOf(c))
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, l.errSlots)
+	})
+
+	t.Run("not a mock", func(t *testing.T) {
+		_, err := layoutOf(reflect.TypeOf(&struct{ Arguments int }{}))
+		require.Error(t, err)
+	})
+
+	t.Run("is cached", func(t *testing.T) {
+		typ := reflect.TypeOf(testdata.NewMockIntyThing(_t).EXPECT().DoThing(1, 2))
+		l1, _ := layoutOf(typ)
+		l2, _ := layoutOf(typ)
+		require.True(t, l1 == l2)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mock := testdata.NewMockRepo[int](_t)
+				e := OK(1)
+				e.Expectorise(mock.EXPECT().Get(1, 2))
+			}()
+		}
+		wg.Wait()
+	})
+}
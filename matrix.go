@@ -0,0 +1,245 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Matrix returns test cases for combinations of the given Expects.
+//
+// The dims map the names of Expect fields on the test case struct T to the
+// options for that field. For example:
+//
+//	tests := tpp.Matrix(
+//		map[string][]tpp.Expect{
+//			"getFoo":  {tpp.OK("foo"), tpp.Err()},
+//			"saveFoo": {tpp.OK(), tpp.Err(), tpp.Unexpected()},
+//		},
+//		func(tt *testCase) {
+//			tt.wantErr = tt.getFoo.Err != nil || tt.saveFoo.Err != nil
+//		},
+//	)
+//
+// By default, this will return the full cartesian product of the options (six
+// test cases in the example above). With the Pairwise option, a smaller set of
+// test cases will be returned which covers every pair of options across any
+// two fields.
+//
+// The outcome function is called once for each generated test case, and should
+// fill in its expected outcome. It may be nil. If the test case has a "name"
+// field which is still empty after outcome is called, it will be named as by
+// NameCases, so the generated names are unique.
+//
+// Matrix panics if a dim isn't an Expect field on T, or if it has no options.
+func Matrix[T any](dims map[string][]Expect, outcome func(*T), options ...MatrixOption) []T {
+	// Parse options
+	var opts matrixOptions
+	for _, o := range options {
+		o(&opts)
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tpp: Matrix test case must be a struct, got %s", typ))
+	}
+
+	// Validate the dims and order them by field declaration order, so that the
+	// generated test cases are deterministic.
+	var (
+		fields  []int
+		choices [][]Expect
+		sizes   []int
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		ee, ok := dims[f.Name]
+		if !ok {
+			continue
+		}
+		if f.Type != expectType {
+			panic(fmt.Sprintf("tpp: Matrix field %q is %s, not tpp.Expect", f.Name, f.Type))
+		}
+		if len(ee) == 0 {
+			panic(fmt.Sprintf("tpp: Matrix field %q has no options", f.Name))
+		}
+		fields = append(fields, i)
+		choices = append(choices, ee)
+		sizes = append(sizes, len(ee))
+	}
+	if len(fields) != len(dims) {
+		for name := range dims {
+			if _, ok := typ.FieldByName(name); !ok {
+				panic(fmt.Sprintf("tpp: Matrix field %q does not exist on %s", name, typ))
+			}
+		}
+	}
+
+	var rows [][]int
+	if opts.pairwise {
+		rows = pairwise(sizes)
+	} else {
+		rows = cartesian(sizes)
+	}
+
+	tcs := make([]T, 0, len(rows))
+	for _, row := range rows {
+		v := reflect.New(typ).Elem()
+		for d, choice := range row {
+			caseField(v, fields[d]).Set(reflect.ValueOf(choices[d][choice]))
+		}
+
+		tc := v.Addr().Interface().(*T)
+		if outcome != nil {
+			outcome(tc)
+		}
+
+		tcs = append(tcs, *tc)
+	}
+
+	// Name the test cases which outcome didn't, as NameCases would, so that two
+	// options for a field which describe the same, like OK("x") and OK("y"),
+	// don't give test cases the same name.
+	if nameField := caseNameField(typ); nameField >= 0 {
+		names := NameCases(tcs)
+		for i := range tcs {
+			if name := caseField(reflect.ValueOf(&tcs[i]).Elem(), nameField); name.String() == "" {
+				name.SetString(names[i])
+			}
+		}
+	}
+
+	return tcs
+}
+
+// matrixOptions is used to configure Matrix.
+type matrixOptions struct {
+	pairwise bool
+}
+
+type MatrixOption func(*matrixOptions)
+
+// Pairwise configures Matrix to generate a pairwise (all-pairs) covering set of
+// test cases rather than the full cartesian product.
+//
+// This means that for any two fields, every combination of their options will
+// appear in at least one test case. This is usually far fewer test cases than
+// the cartesian product when there are more than a few fields, while still
+// exercising every interaction between two dependencies.
+func Pairwise() MatrixOption {
+	return func(opts *matrixOptions) {
+		opts.pairwise = true
+	}
+}
+
+// cartesian returns every combination of choices for dimensions of the given
+// sizes, with the last dimension varying fastest.
+func cartesian(sizes []int) [][]int {
+	rows := [][]int{{}}
+	for _, size := range sizes {
+		var next [][]int
+		for _, row := range rows {
+			for choice := 0; choice < size; choice++ {
+				next = append(next, append(append([]int{}, row...), choice))
+			}
+		}
+		rows = next
+	}
+	return rows
+}
+
+// pairwise returns combinations of choices for dimensions of the given sizes,
+// such that every pair of choices across any two dimensions is covered.
+//
+// This uses the IPOG strategy: start with all pairs of the first two
+// dimensions, then add one dimension at a time, first by extending the
+// existing rows with whichever choice covers the most new pairs ("horizontal
+// growth"), then by adding rows for any pairs which are still uncovered
+// ("vertical growth"). It's greedy, so not always minimal, but deterministic.
+func pairwise(sizes []int) [][]int {
+	if len(sizes) < 3 {
+		return cartesian(sizes)
+	}
+
+	// dontCare marks a choice which isn't needed to cover any pair (yet).
+	const dontCare = -1
+
+	type pair struct {
+		dim, choice, newChoice int
+	}
+
+	rows := cartesian(sizes[:2])
+
+	for k := 2; k < len(sizes); k++ {
+		uncovered := make(map[pair]bool)
+		for d := 0; d < k; d++ {
+			for c := 0; c < sizes[d]; c++ {
+				for nc := 0; nc < sizes[k]; nc++ {
+					uncovered[pair{d, c, nc}] = true
+				}
+			}
+		}
+
+		// Horizontal growth
+		for i, row := range rows {
+			best, bestCount := 0, -1
+			for nc := 0; nc < sizes[k]; nc++ {
+				var count int
+				for d := 0; d < k; d++ {
+					if row[d] != dontCare && uncovered[pair{d, row[d], nc}] {
+						count++
+					}
+				}
+				if count > bestCount {
+					best, bestCount = nc, count
+				}
+			}
+
+			rows[i] = append(row, best)
+			for d := 0; d < k; d++ {
+				delete(uncovered, pair{d, row[d], best})
+			}
+		}
+
+		// Vertical growth
+		for d := 0; d < k; d++ {
+			for c := 0; c < sizes[d]; c++ {
+				for nc := 0; nc < sizes[k]; nc++ {
+					if !uncovered[pair{d, c, nc}] {
+						continue
+					}
+
+					placed := false
+					for _, row := range rows {
+						if row[k] == nc && row[d] == dontCare {
+							row[d] = c
+							placed = true
+							break
+						}
+					}
+
+					if !placed {
+						row := make([]int, k+1)
+						for i := range row {
+							row[i] = dontCare
+						}
+						row[d], row[k] = c, nc
+						rows = append(rows, row)
+					}
+
+					delete(uncovered, pair{d, c, nc})
+				}
+			}
+		}
+	}
+
+	// Any remaining don't-cares can be anything.
+	for _, row := range rows {
+		for i := range row {
+			if row[i] == dontCare {
+				row[i] = 0
+			}
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,164 @@
+package tpp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+)
+
+type matrixCase struct {
+	name    string
+	a       tpp.Expect
+	b       tpp.Expect
+	c       tpp.Expect
+	d       tpp.Expect
+	wantErr bool
+}
+
+// options returns n distinguishable OK Expects.
+func options(n int) []tpp.Expect {
+	ee := make([]tpp.Expect, n)
+	for i := range ee {
+		ee[i] = tpp.OK(i)
+	}
+	return ee
+}
+
+func TestMatrix(t *testing.T) {
+	t.Run("cartesian product", func(t *testing.T) {
+		tcs := tpp.Matrix[matrixCase](
+			map[string][]tpp.Expect{
+				"a": {tpp.OK(1), tpp.Err()},
+				"b": {tpp.OK(2), tpp.Err(), tpp.Unexpected()},
+			},
+			nil,
+		)
+
+		var names []string
+		for _, tc := range tcs {
+			names = append(names, tc.name)
+		}
+		require.Equal(t, []string{
+			"a=OK/b=OK",
+			"a=OK/b=Err",
+			"a=OK/b=Unexpected",
+			"a=Err/b=OK",
+			"a=Err/b=Err",
+			"a=Err/b=Unexpected",
+		}, names)
+	})
+
+	t.Run("outcome is called for each case", func(t *testing.T) {
+		tcs := tpp.Matrix(
+			map[string][]tpp.Expect{
+				"a": {tpp.OK(1), tpp.Err()},
+				"b": {tpp.OK(2), tpp.Err()},
+			},
+			func(tc *matrixCase) {
+				tc.wantErr = tc.a.Err != nil || tc.b.Err != nil
+			},
+		)
+
+		require.Len(t, tcs, 4)
+		for _, tc := range tcs {
+			require.Equal(t, tc.a.Err != nil || tc.b.Err != nil, tc.wantErr)
+		}
+	})
+
+	t.Run("names are unique", func(t *testing.T) {
+		tcs := tpp.Matrix[matrixCase](
+			map[string][]tpp.Expect{
+				"a": {tpp.OK("x"), tpp.OK("y")},
+				"b": {tpp.OK()},
+			},
+			nil,
+		)
+
+		require.Equal(t, "a=OK/b=OK", tcs[0].name)
+		require.Equal(t, "a=OK/b=OK#2", tcs[1].name)
+	})
+
+	t.Run("outcome can set name", func(t *testing.T) {
+		tcs := tpp.Matrix(
+			map[string][]tpp.Expect{"a": {tpp.OK(1)}},
+			func(tc *matrixCase) { tc.name = "mine" },
+		)
+		require.Equal(t, "mine", tcs[0].name)
+	})
+
+	t.Run("pairwise covers all pairs", func(t *testing.T) {
+		dims := map[string][]tpp.Expect{
+			"a": options(3),
+			"b": options(3),
+			"c": options(2),
+			"d": options(3),
+		}
+		tcs := tpp.Matrix[matrixCase](dims, nil, tpp.Pairwise())
+
+		require.Less(t, len(tcs), 3*3*2*3)
+
+		choice := func(tc matrixCase, field string) int {
+			switch field {
+			case "a":
+				return tc.a.Return[0].(int)
+			case "b":
+				return tc.b.Return[0].(int)
+			case "c":
+				return tc.c.Return[0].(int)
+			default:
+				return tc.d.Return[0].(int)
+			}
+		}
+
+		fields := []string{"a", "b", "c", "d"}
+		for i, f1 := range fields {
+			for _, f2 := range fields[i+1:] {
+				for c1 := range dims[f1] {
+					for c2 := range dims[f2] {
+						covered := false
+						for _, tc := range tcs {
+							if choice(tc, f1) == c1 && choice(tc, f2) == c2 {
+								covered = true
+								break
+							}
+						}
+						require.True(t, covered, "%s=%d, %s=%d not covered", f1, c1, f2, c2)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("pairwise is deterministic", func(t *testing.T) {
+		dims := map[string][]tpp.Expect{
+			"a": options(2),
+			"b": options(3),
+			"c": options(2),
+		}
+		require.Equal(
+			t,
+			tpp.Matrix[matrixCase](dims, nil, tpp.Pairwise()),
+			tpp.Matrix[matrixCase](dims, nil, tpp.Pairwise()),
+		)
+	})
+
+	t.Run("panics for unknown field", func(t *testing.T) {
+		require.Panics(t, func() {
+			tpp.Matrix[matrixCase](map[string][]tpp.Expect{"nope": {tpp.OK()}}, nil)
+		})
+	})
+
+	t.Run("panics for non-Expect field", func(t *testing.T) {
+		require.Panics(t, func() {
+			tpp.Matrix[matrixCase](map[string][]tpp.Expect{"wantErr": {tpp.OK()}}, nil)
+		})
+	})
+
+	t.Run("panics for no options", func(t *testing.T) {
+		require.Panics(t, func() {
+			tpp.Matrix[matrixCase](map[string][]tpp.Expect{"a": {}}, nil)
+		})
+	})
+}
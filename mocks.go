@@ -0,0 +1,114 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Provider makes a fresh set of mocks for each test case, from the mock
+// constructors given to Provide. For example:
+//
+//	mocks := tpp.Provide(mymocks.NewFooGetter, mymocks.NewFooSaver)
+//
+//	for _, tt := range tests {
+//		t.Run(tt.name, func(t *testing.T) {
+//			m := mocks.New(t)
+//			tt.getFoo.Expectorise(tpp.Get[*mymocks.FooGetter](m).EXPECT().GetFoo())
+//			tt.saveFoo.Expectorise(tpp.Get[*mymocks.FooSaver](m).EXPECT().SaveFoo(tpp.Arg()))
+//
+//			subject := NewSubject(tpp.Get[*mymocks.FooGetter](m), tpp.Get[*mymocks.FooSaver](m))
+//			...
+//		})
+//	}
+type Provider struct {
+	ctors []reflect.Value
+}
+
+// testingTType is the type of *testing.T, which mock constructors must take.
+var testingTType = reflect.TypeOf((*testing.T)(nil))
+
+// Provide returns a Provider for the given mock constructors.
+//
+// Each constructor must be a func which takes a *testing.T, or an interface
+// which it implements, and returns a mock, like those generated by mockery.
+// Mocks of generic interfaces need instantiating, e.g. mymocks.NewRepo[int].
+//
+// Provide panics if a constructor isn't such a func, or if two of them return
+// the same type.
+func Provide(ctors ...any) *Provider {
+	p := &Provider{}
+	seen := make(map[reflect.Type]bool)
+
+	for _, ctor := range ctors {
+		v := reflect.ValueOf(ctor)
+		typ := v.Type()
+		if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.NumOut() != 1 || !testingTType.AssignableTo(typ.In(0)) {
+			panic(fmt.Sprintf("tpp: Provide given %s, which isn't a mock constructor taking a *testing.T", typ))
+		}
+
+		out := typ.Out(0)
+		if seen[out] {
+			panic(fmt.Sprintf("tpp: Provide given more than one constructor for %s", out))
+		}
+		seen[out] = true
+
+		p.ctors = append(p.ctors, v)
+	}
+
+	return p
+}
+
+// New returns a fresh set of mocks, bound to the given test. This is usually
+// the subtest running a test case, so that the mocks' expectations are
+// asserted when it finishes.
+func (p *Provider) New(t *testing.T) *Mocks {
+	m := &Mocks{}
+
+	tv := reflect.ValueOf(t)
+	for _, ctor := range p.ctors {
+		m.mocks = append(m.mocks, ctor.Call([]reflect.Value{tv})[0])
+	}
+
+	return m
+}
+
+// Mocks is a set of mocks for a test case, made by a Provider.
+type Mocks struct {
+	mocks []reflect.Value
+}
+
+// Get returns the mock of type M from the set.
+//
+// M may also be an interface, in which case the mock which implements it is
+// returned. This is handy for passing mocks straight to the subject.
+//
+// Get panics if there's no such mock, or if M is an interface implemented by
+// more than one of them.
+func Get[M any](m *Mocks) M {
+	typ := reflect.TypeOf((*M)(nil)).Elem()
+
+	for _, v := range m.mocks {
+		if v.Type() == typ {
+			return v.Interface().(M)
+		}
+	}
+
+	var found []reflect.Value
+	if typ.Kind() == reflect.Interface {
+		for _, v := range m.mocks {
+			if v.Type().Implements(typ) {
+				found = append(found, v)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		panic(fmt.Sprintf("tpp: no mock of type %s has been provided", typ))
+	case 1:
+		return found[0].Interface().(M)
+	default:
+		panic(fmt.Sprintf("tpp: more than one mock implements %s", typ))
+	}
+}
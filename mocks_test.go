@@ -0,0 +1,89 @@
+package tpp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+// asserter is implemented by every mock.
+type asserter interface {
+	AssertExpectations(mock.TestingT) bool
+}
+
+func TestProvide(t *testing.T) {
+	mocks := tpp.Provide(
+		testdata.NewMockIntyThing,
+		testdata.NewMockStructyThing,
+		testdata.NewMockRepo[int],
+	)
+
+	for _, tt := range []struct {
+		name     string
+		doThing  tpp.Expect
+		getThing tpp.Expect
+	}{
+		{
+			name:     "OK",
+			doThing:  tpp.OK(1),
+			getThing: tpp.OK(&testdata.Struct{A: 1}),
+		},
+		{
+			name:     "ERR",
+			doThing:  tpp.Err(),
+			getThing: tpp.Unexpected(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.New(t)
+			tt.doThing.Expectorise(tpp.Get[*testdata.MockIntyThing](m).EXPECT().DoThing(1, 2))
+			tt.getThing.Expectorise(tpp.Get[*testdata.MockStructyThing](m).EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+			// Get by the interface the subject takes.
+			n, err := tpp.Get[testdata.IntyThing](m).DoThing(1, 2)
+			if err != nil {
+				return
+			}
+			require.Equal(t, 1, n)
+
+			s, err := tpp.Get[testdata.StructyThing](m).DoThing(context.Background(), nil)
+			require.NoError(t, err)
+			require.Equal(t, 1, s.A)
+		})
+	}
+
+	t.Run("fresh mocks for each case", func(t *testing.T) {
+		m1, m2 := mocks.New(_t()), mocks.New(_t())
+		require.NotSame(t, tpp.Get[*testdata.MockIntyThing](m1), tpp.Get[*testdata.MockIntyThing](m2))
+	})
+
+	t.Run("generic mocks", func(t *testing.T) {
+		m := mocks.New(_t())
+		require.NotNil(t, tpp.Get[*testdata.MockRepo[int]](m))
+		require.NotNil(t, tpp.Get[testdata.Repo[int]](m))
+	})
+
+	t.Run("panics if not provided", func(t *testing.T) {
+		m := mocks.New(_t())
+		require.PanicsWithValue(t, "tpp: no mock of type *testdata.MockFuncyThing has been provided", func() {
+			tpp.Get[*testdata.MockFuncyThing](m)
+		})
+	})
+
+	t.Run("panics if ambiguous", func(t *testing.T) {
+		m := tpp.Provide(testdata.NewMockIntyThing, testdata.NewMockStructyThing).New(_t())
+		require.PanicsWithValue(t, "tpp: more than one mock implements tpp_test.asserter", func() {
+			tpp.Get[asserter](m)
+		})
+	})
+
+	t.Run("panics if not a constructor", func(t *testing.T) {
+		require.Panics(t, func() { tpp.Provide(func() *testdata.MockIntyThing { return nil }) })
+		require.Panics(t, func() { tpp.Provide(testdata.NewMockIntyThing, testdata.NewMockIntyThing) })
+	})
+}
@@ -0,0 +1,125 @@
+package tpp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NameCase returns a name for the given test case, built from its Expect and
+// []Expect fields which differ from their zero values.
+//
+// For example, a test case like this:
+//
+//	{
+//		getFoo:  tpp.Err(),
+//		saveFoo: tpp.Unexpected(),
+//		wantErr: true,
+//	}
+//
+// Will be named "getFoo=Err/saveFoo=Unexpected". Fields are named in the order
+// they are declared, so the name is deterministic. Typed Expects, such as
+// Expect1, are named just like Expects. Fields which aren't Expects don't
+// contribute to the name. If all the Expects are zero-valued, the name is
+// "Default".
+//
+// The test case must be a struct. See NameCases for naming a whole table.
+func NameCase(tc any) string {
+	var parts []string
+	for _, ce := range caseExpects(caseValue(tc)) {
+		if !ce.zero {
+			parts = append(parts, ce.field+"="+ce.desc)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "Default"
+	}
+	return strings.Join(parts, "/")
+}
+
+// NameCases returns a name for each test case in the table, in order.
+//
+// If a test case has a non-empty string field called "name" or "Name", that
+// will be used. Otherwise, the name will be generated by NameCase. The names
+// are guaranteed to be unique across the table: any duplicates will have a
+// "#n" suffix added, where n counts up from 2.
+//
+// For example:
+//
+//	names := tpp.NameCases(tests)
+//	for i, tt := range tests {
+//		t.Run(names[i], func(t *testing.T) {
+//			...
+//		})
+//	}
+func NameCases[T any](tcs []T) []string {
+	names := make([]string, len(tcs))
+	seen := make(map[string]bool, len(tcs))
+
+	for i, tc := range tcs {
+		v := caseValue(tc)
+
+		var name string
+		if idx := caseNameField(v.Type()); idx >= 0 {
+			name = caseField(v, idx).String()
+		}
+		if name == "" {
+			name = NameCase(tc)
+		}
+
+		unique := name
+		for n := 2; seen[unique]; n++ {
+			unique = fmt.Sprintf("%s#%d", name, n)
+		}
+		seen[unique] = true
+		names[i] = unique
+	}
+
+	return names
+}
+
+// describe returns a short description of the Expect, in terms of the
+// functions which would have been used to construct it.
+func (e Expect) describe() string {
+	var desc string
+	switch {
+	case e.Expected != nil && !*e.Expected:
+		desc = "Unexpected"
+	case e.errAt != nil:
+		desc = fmt.Sprintf("ErrAt(%d)", *e.errAt)
+	case isExpectError(e.Err):
+		desc = "Err"
+	case e.Err != nil:
+		desc = "ErrWith"
+	case e.exactReturn:
+		desc = "Return"
+	case e.Expected == nil:
+		desc = "Maybe"
+	default:
+		desc = "OK"
+	}
+
+	if e.argReplacements != nil {
+		desc = "Given." + desc
+	}
+	if e.nTimes > 0 {
+		desc += fmt.Sprintf(".Times(%d)", e.nTimes)
+	}
+	if e.callRange != nil {
+		desc += "." + e.callRange.String()
+	}
+	if len(e.effects) > 0 {
+		desc += ".Then(" + describeEffects(e.effects) + ")"
+	}
+	return desc
+}
+
+// describeMulti returns a short description of the []Expect, as used by
+// ExpectoriseMulti.
+func describeMulti(ee []Expect) string {
+	descs := make([]string, len(ee))
+	for i, e := range ee {
+		descs[i] = e.describe()
+	}
+	return "[" + strings.Join(descs, ",") + "]"
+}
@@ -0,0 +1,135 @@
+package tpp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+)
+
+func TestNameCase(t *testing.T) {
+	type testCase struct {
+		name    string
+		getFoo  tpp.Expect
+		saveFoo tpp.Expect
+		getBars []tpp.Expect
+		wantErr bool
+	}
+
+	for _, tt := range []struct {
+		name     string
+		testCase testCase
+		want     string
+	}{
+		{
+			name:     "zero value",
+			testCase: testCase{},
+			want:     "Default",
+		},
+		{
+			name:     "non-Expect fields are ignored",
+			testCase: testCase{name: "foo", wantErr: true},
+			want:     "Default",
+		},
+		{
+			name:     "single field",
+			testCase: testCase{getFoo: tpp.OK(1)},
+			want:     "getFoo=OK",
+		},
+		{
+			name: "fields in declaration order",
+			testCase: testCase{
+				saveFoo: tpp.Unexpected(),
+				getFoo:  tpp.Err(),
+			},
+			want: "getFoo=Err/saveFoo=Unexpected",
+		},
+		{
+			name: "constructors",
+			testCase: testCase{
+				getFoo:  tpp.ErrWith(errTest),
+				saveFoo: tpp.Return(1, nil),
+			},
+			want: "getFoo=ErrWith/saveFoo=Return",
+		},
+		{
+			name: "Given and Times",
+			testCase: testCase{
+				getFoo: tpp.Given(1).Return(2).Times(3),
+			},
+			want: "getFoo=Given.Return.Times(3)",
+		},
+		{
+			name: "call count range",
+			testCase: testCase{
+				getFoo: tpp.OK().Between(t, 1, 3),
+			},
+			want: "getFoo=OK.Between(1,3)",
+		},
+		{
+			name: "Then",
+			testCase: testCase{
+				getFoo: tpp.Err().Then(tpp.AdvanceBy(time.Second)),
+			},
+			want: "getFoo=Err.Then(AdvanceBy(1s))",
+		},
+		{
+			name: "Maybe",
+			testCase: testCase{
+				getFoo: *(&tpp.Expect{}).Injecting(1),
+			},
+			want: "getFoo=Maybe",
+		},
+		{
+			name: "slice",
+			testCase: testCase{
+				getBars: []tpp.Expect{tpp.OK(1), tpp.Err()},
+			},
+			want: "getBars=[OK,Err]",
+		},
+		{
+			name: "empty slice",
+			testCase: testCase{
+				getBars: []tpp.Expect{},
+			},
+			want: "getBars=[]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tpp.NameCase(tt.testCase))
+		})
+	}
+
+	t.Run("panics for non-struct", func(t *testing.T) {
+		require.Panics(t, func() { tpp.NameCase(42) })
+	})
+}
+
+func TestNameCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		getFoo tpp.Expect
+	}{
+		{name: "OK", getFoo: tpp.OK(1)},
+		{getFoo: tpp.Err()},
+		{getFoo: tpp.Err()},
+		{getFoo: tpp.Unexpected()},
+		{name: "OK"},
+		{},
+	}
+
+	require.Equal(
+		t,
+		[]string{
+			"OK",
+			"getFoo=Err",
+			"getFoo=Err#2",
+			"getFoo=Unexpected",
+			"OK#2",
+			"Default",
+		},
+		tpp.NameCases(tests),
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
 )
 
 // newReflectedMockCall returns an instrumented MockCall by using reflect.
@@ -18,48 +19,46 @@ import (
 // We *only* use reflection for that reason. All of the functions being accessed
 // here are exported functions from the Mockery mock. We must never touch
 // anything unexported here, and perhaps one day this layer can be removed.
+//
+// What we need to know about each type of mock call is cached, so that this is
+// cheap for the tens of thousands of calls in big test suites. See layout.go.
 func newReflectedMockCall(mock MockCall) (*reflectedMockCall, error) {
-	// Validate mock
-	mockval := reflect.ValueOf(mock)
+	layout, err := layoutOf(reflect.TypeOf(mock))
+	if err != nil {
+		return nil, err
+	}
 
+	mockval := reflect.ValueOf(mock)
 	if mockval.Kind() == reflect.Ptr {
 		mockval = mockval.Elem()
 	}
 
-	if mockval.Kind() != reflect.Struct {
-		return nil, errors.New("mock must be struct")
-	}
-
 	// Extract and validate Arguments
-	args := mockval.FieldByName("Arguments")
-
-	if !args.IsValid() {
-		return nil, errors.New("args must be valid")
-	}
-
-	if args.Kind() != reflect.Slice {
-		return nil, errors.New("args must be slice")
-	}
+	args := mockval.FieldByIndex(layout.argsIndex)
 
 	if !args.CanSet() {
 		return nil, errors.New("args must be mutable")
 	}
 
-	// Extract and validate Return
-	ret := reflect.ValueOf(mock).MethodByName("Return")
-	if !ret.IsValid() {
-		return nil, errors.New("given mock has no Return method")
+	// Extract the underlying testify call, so that we can hook into it.
+	call, _ := mock.(*testifymock.Call)
+	if layout.callIndex != nil {
+		call, _ = mockval.FieldByIndex(layout.callIndex).Interface().(*testifymock.Call)
 	}
 
 	return &reflectedMockCall{
 		wrapped:      mock,
+		call:         call,
+		layout:       layout,
 		args:         args,
-		returnMethod: ret,
+		returnMethod: reflect.ValueOf(mock).Method(layout.returnIndex),
 	}, nil
 }
 
 type reflectedMockCall struct {
 	wrapped      MockCall
+	call         *testifymock.Call
+	layout       *mockLayout
 	args         reflect.Value
 	returnMethod reflect.Value
 }
@@ -79,7 +78,7 @@ func (rm *reflectedMockCall) GetArguments() ([]any, error) {
 	return result, nil
 }
 
-// GetArguments returns the mock's arguments.
+// SetArguments sets the mock's arguments.
 //
 // This is just a setter for mock.Arguments. We need this because we want to set
 // the arguments but an interface like tpp.MockCall can't specify field values
@@ -103,34 +102,80 @@ func (rm *reflectedMockCall) SetArguments(args []any) {
 	rm.args.Set(rargs)
 }
 
+// VariadicArgs returns whether the mocked method is variadic, along with the
+// number of args before the variadic ones and the type of the variadic ones.
+//
+// Mockery's Run method takes a func with the same args as the mocked method,
+// so we can tell from that. Bare testify mocks can't tell us.
+func (rm *reflectedMockCall) VariadicArgs() (fixed int, elem reflect.Type, ok bool) {
+	fn := rm.layout.runType
+	if fn == nil || !fn.IsVariadic() {
+		return 0, nil, false
+	}
+
+	return fn.NumIn() - 1, fn.In(fn.NumIn() - 1).Elem(), true
+}
+
+// CallRunAndReturn calls the mock's RunAndReturn method, with a func of the
+// type it takes, as made by makeFn.
+//
+// Only mockery mocks have RunAndReturn. Bare testify mocks don't.
+func (rm *reflectedMockCall) CallRunAndReturn(
+	makeFn func(fnType reflect.Type) (reflect.Value, error),
+) error {
+	if rm.layout.runAndReturnIndex < 0 {
+		return errors.New("mock has no RunAndReturn method")
+	}
+
+	fn, err := makeFn(rm.layout.runAndReturnType.In(0))
+	if err != nil {
+		return err
+	}
+
+	reflect.ValueOf(rm.wrapped).Method(rm.layout.runAndReturnIndex).Call([]reflect.Value{fn})
+	return nil
+}
+
 // CallReturnEmpty calls the mock's Return method with empty values.
 //
-// If an optional error is provided, we will use that for error values.
-func (rm *reflectedMockCall) CallReturnEmpty(retErr error) {
+// If an optional error is provided, we will use that for the error value. It
+// will be returned at index errAt if that's non-negative, and otherwise in the
+// last return value which can hold it.
+//
+// Mocks of generic interfaces, such as MockRepo[T], work just the same. Their
+// Return methods are instantiated by the time we see them, so a T return is
+// zero valued as whatever type T is, e.g., 0 for int and nil for any.
+func (rm *reflectedMockCall) CallReturnEmpty(retErr error, errAt int) {
 	var (
-		returnType                 = rm.returnMethod.Type()
+		returnType                 = rm.layout.returnType
 		returnLen                  = returnType.NumIn()
 		emptyArgs  []reflect.Value = nil
 	)
 
-	if returnLen == 1 && returnType.In(0).Name() == "" && retErr != nil {
-		// Special case: we have an error to return and one argument with an unknown
-		// type. This can happen in two cases. First, when we're handling a bare
-		// testify mock and don't know the Return type. Secondly, when we're handling
-		// a mockery mock with a custom Return type which will be hidden to us. In
-		// the first case, we want to call Return(retErr). The second case is a user
-		// error and will panic.
-		emptyArgs = append(emptyArgs, reflect.ValueOf(retErr))
-	} else {
-		for i := 0; i < returnLen; i++ {
-			argType := returnType.In(i)
-
-			if retErr != nil && argType.Name() == "error" {
-				// We were given an error to return -- use it!
-				emptyArgs = append(emptyArgs, reflect.ValueOf(retErr))
-			} else if !returnType.IsVariadic() || i < returnLen-1 {
-				emptyArgs = append(emptyArgs, reflect.Zero(argType))
+	if isVariadicAnyReturn(returnType) {
+		// Special case: we're handling a bare testify mock, so we don't know the
+		// Return types. The best we can do is return the error, if we have one.
+		if retErr != nil {
+			for i := 0; i < errAt; i++ {
+				emptyArgs = append(emptyArgs, reflect.Zero(anyType))
 			}
+			emptyArgs = append(emptyArgs, reflect.ValueOf(retErr))
+		}
+		rm.returnMethod.Call(emptyArgs)
+		return
+	}
+
+	slot := -1
+	if retErr != nil {
+		slot = rm.mustErrSlot(returnType, retErr, errAt)
+	}
+
+	for i := 0; i < returnLen; i++ {
+		if i == slot {
+			// We were given an error to return -- use it!
+			emptyArgs = append(emptyArgs, reflect.ValueOf(retErr))
+		} else {
+			emptyArgs = append(emptyArgs, reflect.Zero(returnType.In(i)))
 		}
 	}
 
@@ -139,39 +184,101 @@ func (rm *reflectedMockCall) CallReturnEmpty(retErr error) {
 
 // CallReturn calls the mock's Return method with the given args.
 //
-// If an optional retErr is provided, we will use that for error values.
-func (rm *reflectedMockCall) CallReturn(args []any, retErr error, zeroValueErrs bool) error {
+// If an optional retErr is provided, we will use that for the error value. It
+// will be returned at index errAt if that's non-negative, and otherwise in the
+// last return value which can hold it. If zeroValueErrs is set, any other error
+// values which aren't given in args will be zero valued.
+func (rm *reflectedMockCall) CallReturn(
+	args []any,
+	retErr error,
+	errAt int,
+	zeroValueErrs bool,
+) error {
+	returnType := rm.layout.returnType
+	returnArgs := rm.returnArgs(args, retErr, errAt, zeroValueErrs)
+
+	rm.mustArgMatch(returnType, returnArgs)
+
+	rargs, err := toReflectValues(returnArgs, returnType)
+	if err != nil {
+		return fmt.Errorf("toReflectValues failed to transform return values: %s", err)
+	}
+
+	rm.returnMethod.Call(rargs)
+	return nil
+}
+
+// returnArgs returns the args to give the mock's Return method, as described by
+// CallReturn.
+func (rm *reflectedMockCall) returnArgs(
+	args []any,
+	retErr error,
+	errAt int,
+	zeroValueErrs bool,
+) []any {
 	var (
-		returnType = rm.returnMethod.Type()
+		returnType = rm.layout.returnType
 		returnLen  = returnType.NumIn()
 		returnArgs = append([]any{}, args...)
 	)
 
-	if retErr != nil {
-		// We were given an error to return -- use it!
-		returnArgs = append(returnArgs, retErr)
-	} else if zeroValueErrs {
-		// Add a nil error, if applicable
-		for i := len(args); i < returnLen; i++ {
-			if returnType.In(i).Name() == "error" {
-				var emptyErr error
-				returnArgs = append(returnArgs, emptyErr)
-				break
+	switch {
+	case isVariadicAnyReturn(returnType):
+		// We're handling a bare testify mock, so we don't know the Return types.
+		// Put the error where we were told, or otherwise at the end.
+		if retErr != nil {
+			if errAt >= 0 && errAt < len(returnArgs) {
+				returnArgs = append(returnArgs[:errAt+1], returnArgs[errAt:]...)
+				returnArgs[errAt] = retErr
+			} else {
+				returnArgs = append(returnArgs, retErr)
 			}
 		}
-	}
 
-	rm.mustArgMatch(returnType, returnArgs)
+	case retErr != nil || zeroValueErrs:
+		slot := -1
+		if retErr != nil {
+			slot = rm.mustErrSlot(returnType, retErr, errAt)
+		}
 
-	rargs, err := toReflectValues(returnArgs, returnType)
-	if err != nil {
-		return fmt.Errorf("toReflectValues failed to transform return values: %s", err)
+		// Interleave the given args with the error values. The args fill in the
+		// non-error values in order, and any error values after them are nil,
+		// unless we were given an error to return.
+		returnArgs = returnArgs[:0]
+		next := 0
+		for i := 0; i < returnLen; i++ {
+			if i == slot {
+				returnArgs = append(returnArgs, retErr)
+			} else if next < len(args) {
+				returnArgs = append(returnArgs, args[next])
+				next++
+			} else if rm.layout.isErrSlot(i) {
+				returnArgs = append(returnArgs, nil)
+			} else {
+				break
+			}
+		}
+
+		// If there are any left over, the args are wrong. Leave them in so that
+		// mustArgMatch can explain.
+		returnArgs = append(returnArgs, args[next:]...)
 	}
 
-	rm.returnMethod.Call(rargs)
-	return nil
+	return returnArgs
+}
+
+// isErrorType returns whether the given type is an error type. This includes
+// the error interface itself, interfaces which embed it and concrete types
+// which implement it.
+func isErrorType(t reflect.Type) bool {
+	return t.Implements(errorType)
 }
 
+var (
+	anyType   = reflect.TypeOf((*any)(nil)).Elem()
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
 // toReflectValues transforms the |args| of the |method| from `[]any` to
 // `[]reflect.Value`.
 func toReflectValues(args []any, typ reflect.Type) ([]reflect.Value, error) {
@@ -193,7 +300,7 @@ func toReflectValues(args []any, typ reflect.Type) ([]reflect.Value, error) {
 		} else if isVariadicAnyReturn(typ) {
 			// The Return function takes (...any). This means we won't be able to
 			// deduce the type. But the argument is nil, so a bare nil will do.
-			values[i] = reflect.Zero(reflect.TypeOf((*any)(nil)).Elem())
+			values[i] = reflect.Zero(anyType)
 		} else {
 			// Iff the arg type can be nil, use a zero value.
 			switch argType.Kind() {
@@ -232,6 +339,17 @@ func (rm *reflectedMockCall) mustArgMatch(fnType reflect.Type, args []any) {
 	}
 }
 
+// mustErrSlot returns the index of the return value which should hold retErr,
+// and panics with a helpful message if there isn't a suitable one.
+func (rm *reflectedMockCall) mustErrSlot(fnType reflect.Type, retErr error, errAt int) int {
+	slot := rm.layout.errSlot(retErr, errAt)
+	if slot < 0 || slot >= fnType.NumIn() || !reflect.TypeOf(retErr).AssignableTo(fnType.In(slot)) {
+		fn := reflect.ValueOf(rm.wrapped).String()
+		panic(printErrMismatch(fn, fnType, retErr, errAt))
+	}
+	return slot
+}
+
 // argsMatch returns whether the args match the given function type.
 func argsMatch(fnType reflect.Type, args []any) bool {
 	if fnType.Kind() != reflect.Func {
@@ -324,6 +442,32 @@ func printArgMismatch(debugName string, fnType reflect.Type, args []any) string
 	return b.String()
 }
 
+func printErrMismatch(debugName string, fnType reflect.Type, retErr error, errAt int) string {
+	var b strings.Builder
+	b.WriteString("\nReturn() can't return the error!\n")
+	b.WriteString(fmt.Sprintf("    Function: %s\n", debugName))
+
+	b.WriteString("    Expected: (")
+	for i := 0; i < fnType.NumIn(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(fnType.In(i).String())
+	}
+	b.WriteString(")\n")
+
+	if errAt >= 0 {
+		b.WriteString(fmt.Sprintf("    Received: %T at index %d\n", retErr, errAt))
+	} else {
+		b.WriteString(fmt.Sprintf("    Received: %T\n", retErr))
+	}
+	b.WriteString("\n")
+	b.WriteString("If the mock returns a custom error type, use tpp.ErrWith() with an error of that type.\n")
+	b.WriteString("\n")
+
+	return b.String()
+}
+
 // isVariadicAnyReturn returns whether the given type is a function which
 // takes (...any). This is important because e.g., the Return method of the
 // testify call has this signature: func(...interface{}) *mock.Call.
@@ -53,7 +53,7 @@ func TestReflect(t *testing.T) {
 	t.Run("CallReturnEmpty: nil err", func(t *testing.T) {
 		c := testdata.NewMockIntyThing(_t).EXPECT().DoThing(1, 2)
 		rm, _ := newReflectedMockCall(c)
-		rm.CallReturnEmpty(nil)
+		rm.CallReturnEmpty(nil, -1)
 		// DoThing returns (int, error), so empty is 0, nil
 		require.Equal(t, mock.Arguments(mock.Arguments{0, nil}), c.ReturnArguments)
 	})
@@ -62,7 +62,7 @@ func TestReflect(t *testing.T) {
 		errTest := errors.New("ERROR")
 		c := testdata.NewMockIntyThing(_t).EXPECT().DoThing(1, 2)
 		rm, _ := newReflectedMockCall(c)
-		rm.CallReturnEmpty(errTest)
+		rm.CallReturnEmpty(errTest, -1)
 		// DoThing returns (int, error)
 		require.Equal(t, mock.Arguments(mock.Arguments{0, errTest}), c.ReturnArguments)
 	})
@@ -100,7 +100,7 @@ func TestReflect(t *testing.T) {
 				rm, _ := newReflectedMockCall(c)
 
 				call := func() {
-					rm.CallReturn(tt.withReturn.rets, nil, false)
+					rm.CallReturn(tt.withReturn.rets, nil, -1, false)
 				}
 				if tt.wantPanic {
 					is.Panics(call)
@@ -147,7 +147,7 @@ func TestReflectWithTestifyMock(t *testing.T) {
 	t.Run("CallReturnEmpty: nil err", func(t *testing.T) {
 		c := (&mock.Mock{}).On("Test", 1, 2)
 		rm, _ := newReflectedMockCall(c)
-		rm.CallReturnEmpty(nil)
+		rm.CallReturnEmpty(nil, -1)
 		// Because we don't have type information, a zero-value error is just ()
 		require.Equal(t, mock.Arguments(mock.Arguments{}), c.ReturnArguments)
 	})
@@ -156,7 +156,7 @@ func TestReflectWithTestifyMock(t *testing.T) {
 		errTest := errors.New("ERROR")
 		c := (&mock.Mock{}).On("Test", 1, 2)
 		rm, _ := newReflectedMockCall(c)
-		rm.CallReturnEmpty(errTest)
+		rm.CallReturnEmpty(errTest, -1)
 		require.Equal(t, mock.Arguments(mock.Arguments{errTest}), c.ReturnArguments)
 	})
 }
@@ -0,0 +1,331 @@
+package tpp
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Report records which dependency outcomes each test case covers, and writes
+// them out as a table once the test has finished. For example:
+//
+//	func TestXXX(t *testing.T) {
+//		report := tpp.NewReport(t)
+//		for _, tt := range tests {
+//			t.Run(tt.name, func(t *testing.T) {
+//				report.Case(t, &tt)
+//				...
+//			})
+//		}
+//	}
+//
+// Reports are only written when enabled, by setting $TPP_REPORT to the
+// directory to write them to, e.g.:
+//
+//	TPP_REPORT=$PWD/reports go test ./...
+//
+// or by giving NewReport the WithReportDir option.
+//
+// Each test function gets a file named after it, with one row per case and one
+// column per Expect field, along with the pass/fail outcome of the case. The
+// format is Markdown by default, and may be set to one or more of "md", "html"
+// and "junit" with $TPP_REPORT_FORMAT, as a comma-separated list, or the
+// WithReportFormats option.
+//
+// JUnit reports are for CI. They list each Expect field's configuration as a
+// property of the case, and failed cases list the mock calls which may be to
+// blame, with where they were set up: calls which were expected but not made,
+// and calls which were Unexpected. Only calls Expectorised with WithTest, given
+// the case's test or one of its subtests, are listed.
+//
+// When reports aren't enabled, Report does nothing, so it's safe to leave in.
+type Report struct {
+	t       *testing.T
+	dir     string
+	formats []string
+	junit   *junitCases // nil unless one of the formats is JUnit
+
+	mu   sync.Mutex
+	rows []reportRow
+}
+
+// reportRow is one test case in a Report.
+type reportRow struct {
+	name         string
+	expects      []caseExpect
+	expectations []*Expectation
+	outcome      string
+	start        time.Time
+	duration     time.Duration
+}
+
+// NewReport returns a Report for the given test, which will be written once
+// the test and all of its subtests have finished.
+func NewReport(t *testing.T, options ...ReportOption) *Report {
+	// Parse options
+	var opts reportOptions
+	for _, o := range options {
+		o(&opts)
+	}
+
+	r := &Report{t: t, dir: opts.dir}
+	if r.dir == "" {
+		r.dir = os.Getenv("TPP_REPORT")
+	}
+	if r.dir == "" {
+		return r
+	}
+
+	formats := opts.formats
+	if formats == nil {
+		if env := os.Getenv("TPP_REPORT_FORMAT"); env != "" {
+			formats = strings.Split(env, ",")
+		}
+	}
+	if formats == nil {
+		formats = []string{"md"}
+	}
+	for _, f := range formats {
+		f = strings.TrimSpace(f)
+		if _, ok := reportFormats[f]; !ok {
+			t.Fatalf("tpp: unknown report format %q", f)
+		}
+		r.formats = append(r.formats, f)
+		if f == "junit" && r.junit == nil {
+			r.junit = newJUnitCases()
+		}
+	}
+
+	if r.junit != nil {
+		addJUnitReport(r.junit)
+	}
+	t.Cleanup(func() {
+		r.write()
+		if r.junit != nil {
+			removeJUnitReport(r.junit)
+		}
+	})
+	return r
+}
+
+// reportOptions is used to configure NewReport.
+type reportOptions struct {
+	dir     string
+	formats []string
+}
+
+type ReportOption func(*reportOptions)
+
+// WithReportDir enables the Report, writing it to the given directory. This
+// takes precedence over $TPP_REPORT.
+func WithReportDir(dir string) ReportOption {
+	return func(opts *reportOptions) {
+		opts.dir = dir
+	}
+}
+
+// WithReportFormats sets the formats to write the Report in: one or more of
+// "md", "html" and "junit". This takes precedence over $TPP_REPORT_FORMAT.
+func WithReportFormats(formats ...string) ReportOption {
+	return func(opts *reportOptions) {
+		opts.formats = formats
+	}
+}
+
+// Case records a test case in the report, under the name of the given test,
+// which is usually the subtest running the case. tc should be a pointer to the
+// test case struct. Its outcome is recorded once the test has finished.
+func (r *Report) Case(t *testing.T, tc any) {
+	if r.dir == "" {
+		return
+	}
+
+	// Take the row now, so that the rows are in the order the cases started,
+	// even if they're run in parallel.
+	r.mu.Lock()
+	i := len(r.rows)
+	r.rows = append(r.rows, reportRow{
+		name:  strings.TrimPrefix(t.Name(), r.t.Name()+"/"),
+		start: time.Now(),
+	})
+	r.mu.Unlock()
+
+	if r.junit != nil {
+		r.junit.watch(t.Name())
+	}
+
+	t.Cleanup(func() {
+		v := reflect.ValueOf(tc)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		expects := caseExpects(caseValue(v.Interface()))
+
+		var expectations []*Expectation
+		if r.junit != nil {
+			expectations = r.junit.take(t.Name())
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.rows[i].expects = expects
+		r.rows[i].expectations = expectations
+		r.rows[i].outcome = outcome(t)
+		r.rows[i].duration = time.Since(r.rows[i].start)
+	})
+}
+
+// outcome returns the outcome of the finished test, as shown in reports.
+func outcome(t *testing.T) string {
+	switch {
+	case t.Failed():
+		return "FAIL"
+	case t.Skipped():
+		return "SKIP"
+	default:
+		return "PASS"
+	}
+}
+
+// write writes the report in each of its formats.
+func (r *Report) write() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		r.t.Errorf("tpp: creating report directory: %v", err)
+		return
+	}
+
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(r.t.Name())
+	for _, f := range r.formats {
+		var buf bytes.Buffer
+		format := reportFormats[f]
+		if err := format.write(&buf, r); err != nil {
+			r.t.Errorf("tpp: writing %s report: %v", f, err)
+			continue
+		}
+
+		path := filepath.Join(r.dir, name+format.ext)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			r.t.Errorf("tpp: writing %s report: %v", f, err)
+		}
+	}
+}
+
+// columns returns the names of the Expect fields across all the rows, in the
+// order they're declared.
+func (r *Report) columns() []string {
+	var cols []string
+	seen := make(map[string]bool)
+	for _, row := range r.rows {
+		for _, ce := range row.expects {
+			if !seen[ce.field] {
+				seen[ce.field] = true
+				cols = append(cols, ce.field)
+			}
+		}
+	}
+	return cols
+}
+
+// cells returns the row's descriptions for each of the given columns.
+func (row reportRow) cells(cols []string) []string {
+	cells := make([]string, len(cols))
+	for i, col := range cols {
+		for _, ce := range row.expects {
+			if ce.field == col {
+				cells[i] = ce.desc
+			}
+		}
+	}
+	return cells
+}
+
+// reportFormats are the supported formats of Report, keyed by name.
+var reportFormats = map[string]struct {
+	ext   string
+	write func(*bytes.Buffer, *Report) error
+}{
+	"md":    {ext: ".md", write: writeMarkdownReport},
+	"html":  {ext: ".html", write: writeHTMLReport},
+	"junit": {ext: ".xml", write: writeJUnitReport},
+}
+
+func writeMarkdownReport(buf *bytes.Buffer, r *Report) error {
+	cols := r.columns()
+	escape := strings.NewReplacer("|", `\|`, "\n", " ").Replace
+
+	fmt.Fprintf(buf, "# %s\n\n", r.t.Name())
+
+	fmt.Fprint(buf, "| Case |")
+	for _, col := range cols {
+		fmt.Fprintf(buf, " %s |", escape(col))
+	}
+	fmt.Fprint(buf, " Outcome |\n|---|")
+	for range cols {
+		fmt.Fprint(buf, "---|")
+	}
+	fmt.Fprint(buf, "---|\n")
+
+	for _, row := range r.rows {
+		fmt.Fprintf(buf, "| %s |", escape(row.name))
+		for _, cell := range row.cells(cols) {
+			fmt.Fprintf(buf, " %s |", escape(cell))
+		}
+		fmt.Fprintf(buf, " %s |\n", row.outcome)
+	}
+	return nil
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+table { border-collapse: collapse; font-family: monospace; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.PASS { background: #dfd; }
+.FAIL { background: #fdd; }
+.SKIP { background: #eee; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<table>
+<tr><th>Case</th>{{range .Columns}}<th>{{.}}</th>{{end}}<th>Outcome</th></tr>
+{{- range .Rows}}
+<tr class="{{.Outcome}}"><td>{{.Name}}</td>{{range .Cells}}<td>{{.}}</td>{{end}}<td>{{.Outcome}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func writeHTMLReport(buf *bytes.Buffer, r *Report) error {
+	type row struct {
+		Name, Outcome string
+		Cells         []string
+	}
+
+	cols := r.columns()
+	data := struct {
+		Name    string
+		Columns []string
+		Rows    []row
+	}{Name: r.t.Name(), Columns: cols}
+
+	for _, rr := range r.rows {
+		data.Rows = append(data.Rows, row{Name: rr.name, Outcome: rr.outcome, Cells: rr.cells(cols)})
+	}
+
+	return htmlReport.Execute(buf, data)
+}
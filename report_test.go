@@ -0,0 +1,144 @@
+package tpp_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+type reportCase struct {
+	name    string
+	getFoo  tpp.Expect
+	saveFoo []tpp.Expect
+	skip    bool
+}
+
+var reportCases = []reportCase{
+	{
+		name:   "OK",
+		getFoo: tpp.OK(1),
+	},
+	{
+		name:    "getFoo fails",
+		getFoo:  tpp.Err(),
+		saveFoo: []tpp.Expect{},
+	},
+	{
+		name:    "skipped",
+		getFoo:  tpp.OK(1).Times(2),
+		saveFoo: []tpp.Expect{tpp.OK(1), tpp.Unexpected()},
+		skip:    true,
+	},
+}
+
+// runReport runs a test with a Report over reportCases, and returns the
+// contents of the report files written in each format.
+func runReport(t *testing.T, formats ...string) map[string]string {
+	dir := t.TempDir()
+	t.Setenv("TPP_REPORT", dir)
+	t.Setenv("TPP_REPORT_FORMAT", "")
+	if len(formats) > 0 {
+		t.Setenv("TPP_REPORT_FORMAT", formats[0])
+	}
+
+	t.Run("TestSubject", func(t *testing.T) {
+		report := tpp.NewReport(t)
+		for _, tt := range reportCases {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				report.Case(t, &tt)
+				if tt.skip {
+					t.Skip()
+				}
+
+				mock := testdata.NewMockIntyThing(_t())
+				tt.getFoo.Expectorise(mock.EXPECT().DoThing(1, 2))
+			})
+		}
+	})
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		files[e.Name()] = string(b)
+	}
+	return files
+}
+
+func TestReport(t *testing.T) {
+	t.Run("writes markdown by default", func(t *testing.T) {
+		files := runReport(t)
+
+		name := "TestReport_writes_markdown_by_default_TestSubject.md"
+		require.Equal(t, []string{name}, keys(files))
+		require.Equal(t, `# TestReport/writes_markdown_by_default/TestSubject
+
+| Case | getFoo | saveFoo | Outcome |
+|---|---|---|---|
+| OK | OK | Maybe | PASS |
+| getFoo_fails | Err | [] | PASS |
+| skipped | OK.Times(2) | [OK,Unexpected] | SKIP |
+`, files[name])
+	})
+
+	t.Run("writes html", func(t *testing.T) {
+		files := runReport(t, "md,html")
+
+		name := "TestReport_writes_html_TestSubject.html"
+		require.Len(t, files, 2)
+		require.Contains(t, files[name], `<tr class="SKIP"><td>skipped</td><td>OK.Times(2)</td><td>[OK,Unexpected]</td><td>SKIP</td></tr>`)
+	})
+
+	t.Run("writes junit", func(t *testing.T) {
+		files := runReport(t, "junit")
+
+		xml := files["TestReport_writes_junit_TestSubject.xml"]
+		require.Contains(t, xml, `<testsuite name="TestReport/writes_junit/TestSubject" tests="3" failures="0" skipped="1"`)
+		require.Contains(t, xml, `<property name="saveFoo" value="[OK,Unexpected]"></property>`)
+		require.Contains(t, xml, `<skipped></skipped>`)
+	})
+
+	t.Run("options take precedence over env", func(t *testing.T) {
+		envDir, dir := t.TempDir(), t.TempDir()
+		t.Setenv("TPP_REPORT", envDir)
+		t.Setenv("TPP_REPORT_FORMAT", "md")
+
+		t.Run("TestSubject", func(t *testing.T) {
+			report := tpp.NewReport(t, tpp.WithReportDir(dir), tpp.WithReportFormats("html", "junit"))
+			report.Case(t, &reportCases[0])
+		})
+
+		entries, err := os.ReadDir(envDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		entries, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "TestReport_options_take_precedence_over_env_TestSubject.html", entries[0].Name())
+		require.Equal(t, "TestReport_options_take_precedence_over_env_TestSubject.xml", entries[1].Name())
+	})
+
+	t.Run("does nothing unless enabled", func(t *testing.T) {
+		t.Setenv("TPP_REPORT", "")
+		report := tpp.NewReport(t)
+		report.Case(t, &reportCases[0])
+	})
+}
+
+func keys(m map[string]string) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
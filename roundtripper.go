@@ -0,0 +1,149 @@
+package tpp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"github.com/mattavos/tpp/internal/stubt"
+)
+
+// RoundTripper is an http.RoundTripper whose responses are configured with
+// Expects. It stands in for HTTP dependencies which are reached through an
+// *http.Client, without needing a server. For example:
+//
+//	rt := tpp.NewRoundTripper(t)
+//	tt.getUser.Expectorise(rt.On("GET", "https://api.example.com/users/1"))
+//
+//	subject := subject.New(&http.Client{Transport: rt})
+//
+// Each request is configured like a mock call which takes the request's query
+// and body, and returns the response's status and body, and an error:
+//
+//	func(query url.Values, body string) (status int, body any, err error)
+//
+// So tpp.Given() matches on the query and body. The query is never nil, so use
+// url.Values{} to match a request without one.
+//
+// A zero status is taken to be 200 OK. The body returned is used as is if it's
+// a string or []byte, and is JSON encoded otherwise. An error (e.g., from
+// tpp.Err()) is returned by RoundTrip, so the client sees it as a transport
+// error.
+//
+// Requests which aren't expected fail the test, as they would for a mock, and
+// the expectations are asserted when the test finishes.
+type RoundTripper struct {
+	mock testifymock.Mock
+}
+
+// NewRoundTripper returns a new RoundTripper, whose expectations are asserted
+// when the test finishes.
+func NewRoundTripper(t interface {
+	testifymock.TestingT
+	Cleanup(func())
+}) *RoundTripper {
+	rt := &RoundTripper{}
+
+	// The client may call RoundTrip from any goroutine, where FailNow can't be
+	// called. See stubt.T.
+	rt.mock.Test(stubt.T{TestingT: t})
+
+	t.Cleanup(func() { rt.mock.AssertExpectations(t) })
+
+	return rt
+}
+
+// On returns a call for requests with the given method and URL, which should be
+// Expectorised. Its args are tpp.Arg()s, to be filled in by tpp.Given().
+//
+// The URL's query, if it has one, is ignored: use tpp.Given() to match on it.
+func (rt *RoundTripper) On(method, rawURL string) *RoundTripperCall {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(fmt.Sprintf("tpp: invalid URL for RoundTripper: %v", err))
+	}
+
+	return &RoundTripperCall{Call: rt.mock.On(requestKey(method, u), Arg(), Arg())}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret, ok := stubt.Called(&rt.mock, requestKey(req.Method, req.URL), req.URL.Query(), string(body))
+	if !ok {
+		return nil, errors.Errorf("tpp: unexpected request: %s %s", req.Method, req.URL)
+	}
+
+	if err := ret.Error(2); err != nil {
+		return nil, err
+	}
+
+	return newResponse(req, ret.Int(0), ret.Get(1))
+}
+
+// RoundTripperCall is a call for requests to a RoundTripper, which is
+// configured like a mockery mock call.
+type RoundTripperCall struct {
+	*testifymock.Call
+}
+
+// Run sets a handler to be called with the query and body of each request,
+// before responding.
+func (c *RoundTripperCall) Run(run func(query url.Values, body string)) *RoundTripperCall {
+	c.Call.Run(func(args testifymock.Arguments) {
+		run(args[0].(url.Values), args[1].(string))
+	})
+	return c
+}
+
+// Return sets the response. See RoundTripper.
+func (c *RoundTripperCall) Return(status int, body any, err error) *RoundTripperCall {
+	c.Call.Return(status, body, err)
+	return c
+}
+
+// requestKey returns the name of the mock method for requests with the given
+// method and URL, e.g., "GET https://api.example.com/users/1".
+func requestKey(method string, u *url.URL) string {
+	return method + " " + u.Scheme + "://" + u.Host + u.Path
+}
+
+func newResponse(req *http.Request, status int, body any) (*http.Response, error) {
+	b, contentType, err := stubt.Body(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpp: encoding response body")
+	}
+
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	status = stubt.Status(status)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package tpp_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+)
+
+func TestRoundTripper(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	getFooErr := tpp.Err()
+
+	for _, tt := range []struct {
+		name       string
+		getFoo     tpp.Expect
+		url        string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name:       "OK: JSON body",
+			getFoo:     tpp.Return(http.StatusOK, user{Name: "foo"}, nil),
+			url:        "https://api.example.com/foo",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"foo"}`,
+		},
+		{
+			name:       "OK: string body",
+			getFoo:     tpp.OK(http.StatusAccepted, "accepted"),
+			url:        "https://api.example.com/foo",
+			wantStatus: http.StatusAccepted,
+			wantBody:   "accepted",
+		},
+		{
+			name:       "OK: zero value",
+			url:        "https://api.example.com/foo",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OK: Given() query and body",
+			getFoo:     tpp.Given(url.Values{"q": {"x"}}, "hello").Return(http.StatusOK, "hi", nil),
+			url:        "https://api.example.com/foo?q=x",
+			body:       "hello",
+			wantStatus: http.StatusOK,
+			wantBody:   "hi",
+		},
+		{
+			name:       "OK: Given() matcher",
+			getFoo:     tpp.Given(testifymock.Anything, testifymock.Anything).Return(http.StatusOK, "hi", nil),
+			url:        "https://api.example.com/foo?q=y",
+			wantStatus: http.StatusOK,
+			wantBody:   "hi",
+		},
+		{
+			name:    "ERR: Err() is transport error",
+			getFoo:  getFooErr,
+			url:     "https://api.example.com/foo",
+			wantErr: getFooErr.Err,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := tpp.NewRoundTripper(t)
+			tt.getFoo.Expectorise(rt.On("POST", "https://api.example.com/foo"))
+
+			client := &http.Client{Transport: rt}
+			resp, err := client.Post(tt.url, "text/plain", strings.NewReader(tt.body))
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantBody, string(b))
+		})
+	}
+}
+
+func TestRoundTripperFails(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		getFoo tpp.Expect
+		url    string
+	}{
+		{
+			name:   "Unexpected() request is made",
+			getFoo: tpp.Unexpected(),
+			url:    "https://api.example.com/foo",
+		},
+		{
+			name:   "Given() doesn't match",
+			getFoo: tpp.Given(url.Values{"q": {"x"}}, "").Return(http.StatusOK, "x", nil),
+			url:    "https://api.example.com/foo?q=y",
+		},
+		{
+			name: "other URL",
+			url:  "https://api.example.com/bar",
+		},
+		{
+			name: "other host",
+			url:  "https://example.com/foo",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := _t()
+			rt := tpp.NewRoundTripper(ft)
+			tt.getFoo.Expectorise(rt.On("GET", "https://api.example.com/foo"))
+
+			client := &http.Client{Transport: rt}
+			_, err := client.Get(tt.url)
+			require.Error(t, err)
+			require.True(t, ft.Failed())
+		})
+	}
+
+	t.Run("expected request isn't made", func(t *testing.T) {
+		ct := &cleanupT{}
+		rt := tpp.NewRoundTripper(ct)
+		e := tpp.OK(http.StatusOK, "")
+		e.Expectorise(rt.On("GET", "https://api.example.com/foo"))
+
+		ct.runCleanups()
+		require.True(t, ct.failed)
+	})
+}
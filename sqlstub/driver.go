@@ -0,0 +1,216 @@
+package sqlstub
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+
+	"github.com/mattavos/tpp/internal/stubt"
+)
+
+// This is the database/sql/driver implementation, which answers each statement
+// by calling the DB's mock.
+
+// errUnexpected is returned for statements which failed the test.
+func errUnexpected(what string) error {
+	return fmt.Errorf("sqlstub: unexpected %s", what)
+}
+
+type connector struct {
+	db *DB
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{db: c.db}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return stubDriver{c.db}
+}
+
+type stubDriver struct {
+	db *DB
+}
+
+func (d stubDriver) Open(string) (driver.Conn, error) {
+	return &conn{db: d.db}, nil
+}
+
+type conn struct {
+	db *DB
+}
+
+var (
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return c.Prepare(query)
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	if err := c.txCalled("Begin"); err != nil {
+		return nil, err
+	}
+	return tx{c}, nil
+}
+
+// CheckNamedValue accepts all args as they are, so that they're matched against
+// tpp.Given() as they were passed to database/sql.
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	key, ok := c.db.match(kindQuery, query)
+	if !ok {
+		c.db.t.Errorf("sqlstub: unexpected query: %s", query)
+		return nil, errUnexpected("query")
+	}
+
+	ret, ok := stubt.Called(&c.db.mock, key, values(args))
+	if !ok {
+		return nil, errUnexpected("query")
+	}
+
+	if err := ret.Error(1); err != nil {
+		return nil, err
+	}
+
+	// Rows given to QueryCall.Return are checked already, but not those given
+	// to its *mock.Call directly.
+	r := ret.Get(0).(Rows)
+	if err := r.check(); err != nil {
+		c.db.t.Errorf("%s, for query: %s", err, query)
+		return nil, err
+	}
+
+	return &rows{rows: r}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	key, ok := c.db.match(kindExec, query)
+	if !ok {
+		c.db.t.Errorf("sqlstub: unexpected exec: %s", query)
+		return nil, errUnexpected("exec")
+	}
+
+	ret, ok := stubt.Called(&c.db.mock, key, values(args))
+	if !ok {
+		return nil, errUnexpected("exec")
+	}
+
+	if err := ret.Error(1); err != nil {
+		return nil, err
+	}
+
+	return result{ret.Get(0).(Result)}, nil
+}
+
+func (c *conn) txCalled(method string) error {
+	ret, ok := stubt.Called(&c.db.mock, method)
+	if !ok {
+		return errUnexpected(method)
+	}
+	return ret.Error(0)
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	vals := make([]driver.Value, len(args))
+	for i, a := range args {
+		vals[i] = a.Value
+	}
+	return vals
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, named(args))
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+	}
+	return nv
+}
+
+type tx struct {
+	conn *conn
+}
+
+func (t tx) Commit() error {
+	return t.conn.txCalled("Commit")
+}
+
+func (t tx) Rollback() error {
+	return t.conn.txCalled("Rollback")
+}
+
+type rows struct {
+	rows Rows
+	next int
+}
+
+func (r *rows) Columns() []string {
+	return r.rows.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows.Values) {
+		return io.EOF
+	}
+	copy(dest, r.rows.Values[r.next])
+	r.next++
+	return nil
+}
+
+type result struct {
+	res Result
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.res.LastInsertID, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.res.RowsAffected, nil
+}
@@ -0,0 +1,326 @@
+// Package sqlstub provides a *sql.DB to stand in for databases in tpp tests,
+// whose statements are configured with tpp.Expects just like mocks are.
+//
+// For example:
+//
+//	func TestXXX(t *testing.T) {
+//		for _, tt := range []struct {
+//			name    string
+//			getUser tpp.Expect
+//			wantErr bool
+//		}{
+//			{
+//				name: "OK",
+//				getUser: tpp.Given(1).Return(sqlstub.Rows{
+//					Columns: []string{"name"},
+//					Values:  [][]driver.Value{{"foo"}},
+//				}, nil),
+//			},
+//			{
+//				name:    "ERR: getUser",
+//				getUser: tpp.Err(),
+//				wantErr: true,
+//			},
+//		} {
+//			t.Run(tt.name, func(t *testing.T) {
+//				db := sqlstub.New(t)
+//				tt.getUser.Expectorise(db.ExpectQuery(sqlstub.Exact("SELECT name FROM users WHERE id = ?")))
+//
+//				subject := subject.New(db.DB)
+//				err := subject.XXX()
+//
+//				require.Equal(t, tt.wantErr, err != nil)
+//			})
+//		}
+//	}
+//
+// Queries and execs are configured like mock calls which take the statement's
+// args, and return Rows or a Result, and an error:
+//
+//	func(args ...driver.Value) (Rows, error)
+//	func(args ...driver.Value) (Result, error)
+//
+// So tpp.Given() matches on the args. These are passed to the stub as given to
+// database/sql, so e.g., an int arg is matched by tpp.Given(1). Begin, Commit
+// and Rollback are configured like mock calls which take nothing and return an
+// error.
+//
+// Statements are matched against the expected ones in the order those were
+// first registered. Statements which aren't expected, or which are expected to
+// be Unexpected(), fail the test, as they would for a mock.
+package sqlstub
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	testifymock "github.com/stretchr/testify/mock"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/internal/stubt"
+)
+
+// DB is a *sql.DB whose statements are configured with tpp.Expects.
+//
+// It's closed, and its expectations asserted, when the test finishes.
+type DB struct {
+	*sql.DB
+
+	mock testifymock.Mock
+	t    TestingT
+
+	mu         sync.Mutex
+	statements []statement
+}
+
+// TestingT is the subset of testing.T which the DB needs.
+type TestingT interface {
+	testifymock.TestingT
+	Cleanup(func())
+}
+
+// New opens a new DB, which is closed when the test finishes.
+func New(t TestingT) *DB {
+	db := &DB{t: t}
+
+	// database/sql may call the driver from any goroutine, where FailNow can't
+	// be called. See stubt.T.
+	db.mock.Test(stubt.T{TestingT: t})
+
+	db.DB = sql.OpenDB(connector{db})
+	t.Cleanup(func() { db.Close() })
+	t.Cleanup(func() { db.mock.AssertExpectations(t) })
+
+	return db
+}
+
+// ExpectQuery returns a call for queries matching m, which should be
+// Expectorised.
+func (db *DB) ExpectQuery(m Matcher) *QueryCall {
+	key := db.register(kindQuery, m)
+	return &QueryCall{Call: db.mock.On(key, tpp.Arg())}
+}
+
+// ExpectExec returns a call for execs matching m, which should be Expectorised.
+func (db *DB) ExpectExec(m Matcher) *ExecCall {
+	key := db.register(kindExec, m)
+	return &ExecCall{Call: db.mock.On(key, tpp.Arg())}
+}
+
+// ExpectBegin returns a call for beginning a transaction, which should be
+// Expectorised.
+func (db *DB) ExpectBegin() *TxCall {
+	return &TxCall{Call: db.mock.On("Begin")}
+}
+
+// ExpectCommit returns a call for committing a transaction, which should be
+// Expectorised.
+func (db *DB) ExpectCommit() *TxCall {
+	return &TxCall{Call: db.mock.On("Commit")}
+}
+
+// ExpectRollback returns a call for rolling back a transaction, which should be
+// Expectorised.
+func (db *DB) ExpectRollback() *TxCall {
+	return &TxCall{Call: db.mock.On("Rollback")}
+}
+
+// Rows are the rows returned by a query. Each of the Values must have a value
+// for each of the Columns.
+type Rows struct {
+	Columns []string
+	Values  [][]driver.Value
+}
+
+// check returns an error if any of the rows doesn't have a value for each
+// column.
+func (r Rows) check() error {
+	for i, row := range r.Values {
+		if len(row) != len(r.Columns) {
+			return fmt.Errorf("sqlstub: row %d has %d value(s), but there are %d column(s)", i, len(row), len(r.Columns))
+		}
+	}
+	return nil
+}
+
+// Result is the result of an exec.
+type Result struct {
+	LastInsertID int64
+	RowsAffected int64
+}
+
+// -----------------------------------------------------------------------------
+// Calls -----------------------------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// QueryCall is a call for a query, which is configured like a mockery mock call.
+type QueryCall struct {
+	*testifymock.Call
+}
+
+// Run sets a handler to be called with the args of each matching query, before
+// returning.
+func (c *QueryCall) Run(run func(args ...driver.Value)) *QueryCall {
+	c.Call.Run(func(args testifymock.Arguments) {
+		run(args[0].([]driver.Value)...)
+	})
+	return c
+}
+
+// Return sets the rows returned by the query, or its error. It panics if any
+// of the rows doesn't have a value for each column.
+func (c *QueryCall) Return(rows Rows, err error) *QueryCall {
+	if err := rows.check(); err != nil {
+		panic(err)
+	}
+	c.Call.Return(rows, err)
+	return c
+}
+
+// PackedVariadic tells tpp that the args are passed to testify as one slice.
+func (c *QueryCall) PackedVariadic() {}
+
+// ExecCall is a call for an exec, which is configured like a mockery mock call.
+type ExecCall struct {
+	*testifymock.Call
+}
+
+// Run sets a handler to be called with the args of each matching exec, before
+// returning.
+func (c *ExecCall) Run(run func(args ...driver.Value)) *ExecCall {
+	c.Call.Run(func(args testifymock.Arguments) {
+		run(args[0].([]driver.Value)...)
+	})
+	return c
+}
+
+// Return sets the result of the exec, or its error.
+func (c *ExecCall) Return(result Result, err error) *ExecCall {
+	c.Call.Return(result, err)
+	return c
+}
+
+// PackedVariadic tells tpp that the args are passed to testify as one slice.
+func (c *ExecCall) PackedVariadic() {}
+
+// TxCall is a call for beginning, committing or rolling back a transaction,
+// which is configured like a mockery mock call.
+type TxCall struct {
+	*testifymock.Call
+}
+
+// Run sets a handler to be called before returning.
+func (c *TxCall) Run(run func()) *TxCall {
+	c.Call.Run(func(testifymock.Arguments) {
+		run()
+	})
+	return c
+}
+
+// Return sets the error returned.
+func (c *TxCall) Return(err error) *TxCall {
+	c.Call.Return(err)
+	return c
+}
+
+// -----------------------------------------------------------------------------
+// Matchers --------------------------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// Matcher matches the SQL of a statement.
+type Matcher interface {
+	Match(query string) bool
+	String() string
+}
+
+// Exact matches statements whose SQL is the same as the given SQL, ignoring
+// differences in whitespace.
+func Exact(query string) Matcher {
+	return exact(normaliseSpace(query))
+}
+
+type exact string
+
+func (e exact) Match(query string) bool {
+	return string(e) == normaliseSpace(query)
+}
+
+func (e exact) String() string {
+	return fmt.Sprintf("%q", string(e))
+}
+
+// Regexp matches statements whose SQL matches the given regular expression.
+// It panics if the expression can't be parsed.
+func Regexp(expr string) Matcher {
+	return regexpMatcher{regexp.MustCompile(expr)}
+}
+
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+func (r regexpMatcher) Match(query string) bool {
+	return r.re.MatchString(query)
+}
+
+func (r regexpMatcher) String() string {
+	return "/" + r.re.String() + "/"
+}
+
+func normaliseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// -----------------------------------------------------------------------------
+// Statements ------------------------------------------------------------------
+// -----------------------------------------------------------------------------
+
+type kind string
+
+const (
+	kindQuery kind = "Query"
+	kindExec  kind = "Exec"
+)
+
+// statement is an expected statement, whose calls are registered on the mock
+// under key.
+type statement struct {
+	kind    kind
+	matcher Matcher
+	key     string
+}
+
+// register registers an expected statement, if it isn't already, and returns
+// the key for its calls.
+func (db *DB) register(k kind, m Matcher) string {
+	key := fmt.Sprintf("%s %s", k, m)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, s := range db.statements {
+		if s.key == key {
+			return key
+		}
+	}
+	db.statements = append(db.statements, statement{kind: k, matcher: m, key: key})
+
+	return key
+}
+
+// match returns the key of the first expected statement which matches.
+func (db *DB) match(k kind, query string) (string, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, s := range db.statements {
+		if s.kind == k && s.matcher.Match(query) {
+			return s.key, true
+		}
+	}
+	return "", false
+}
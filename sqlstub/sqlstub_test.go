@@ -0,0 +1,268 @@
+package sqlstub_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/sqlstub"
+)
+
+// We use this dummy testing.T to pass into the code under test, where we want
+// the DB to fail the test, since failing *our* test would be wrong.
+var _t = func() *testing.T {
+	return &testing.T{}
+}
+
+const getUser = "SELECT name FROM users WHERE id = ?"
+
+func TestQuery(t *testing.T) {
+	getUserErr := tpp.Err()
+
+	for _, tt := range []struct {
+		name      string
+		matcher   sqlstub.Matcher
+		getUser   tpp.Expect
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:    "OK: Given() args",
+			matcher: sqlstub.Exact(getUser),
+			getUser: tpp.Given(1).Return(sqlstub.Rows{
+				Columns: []string{"name"},
+				Values:  [][]driver.Value{{"foo"}, {"bar"}},
+			}, nil),
+			wantNames: []string{"foo", "bar"},
+		},
+		{
+			name:    "OK: Given() matcher",
+			matcher: sqlstub.Exact(getUser),
+			getUser: tpp.Given(testifymock.Anything).Return(sqlstub.Rows{
+				Columns: []string{"name"},
+				Values:  [][]driver.Value{{"foo"}},
+			}, nil),
+			wantNames: []string{"foo"},
+		},
+		{
+			name:    "OK: OK()",
+			matcher: sqlstub.Regexp(`^SELECT name FROM users`),
+			getUser: tpp.OK(sqlstub.Rows{
+				Columns: []string{"name"},
+				Values:  [][]driver.Value{{"foo"}},
+			}),
+			wantNames: []string{"foo"},
+		},
+		{
+			name:    "OK: Exact() ignores whitespace",
+			matcher: sqlstub.Exact("SELECT name\n\tFROM users\n\tWHERE id = ?"),
+			getUser: tpp.OK(sqlstub.Rows{
+				Columns: []string{"name"},
+				Values:  [][]driver.Value{{"foo"}},
+			}),
+			wantNames: []string{"foo"},
+		},
+		{
+			name:    "OK: zero value",
+			matcher: sqlstub.Exact(getUser),
+		},
+		{
+			name:    "ERR: Err()",
+			matcher: sqlstub.Exact(getUser),
+			getUser: getUserErr,
+			wantErr: getUserErr.Err,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlstub.New(t)
+			tt.getUser.Expectorise(db.ExpectQuery(tt.matcher))
+
+			rows, err := db.QueryContext(context.Background(), getUser, 1)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			require.NoError(t, err)
+			defer rows.Close()
+
+			var names []string
+			for rows.Next() {
+				var name string
+				require.NoError(t, rows.Scan(&name))
+				names = append(names, name)
+			}
+			require.NoError(t, rows.Err())
+			require.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestExec(t *testing.T) {
+	const insert = "INSERT INTO users (name, age) VALUES (?, ?)"
+
+	t.Run("OK: Given() args", func(t *testing.T) {
+		db := sqlstub.New(t)
+		e := tpp.Given("foo", 42).Return(sqlstub.Result{LastInsertID: 7, RowsAffected: 1}, nil)
+		e.Expectorise(db.ExpectExec(sqlstub.Exact(insert)))
+
+		res, err := db.Exec(insert, "foo", 42)
+		require.NoError(t, err)
+
+		id, _ := res.LastInsertId()
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(7), id)
+		require.Equal(t, int64(1), n)
+	})
+
+	t.Run("OK: prepared", func(t *testing.T) {
+		db := sqlstub.New(t)
+		e := tpp.Given("foo", 42).Return(sqlstub.Result{RowsAffected: 1}, nil)
+		e.Expectorise(db.ExpectExec(sqlstub.Exact(insert)))
+
+		stmt, err := db.Prepare(insert)
+		require.NoError(t, err)
+		defer stmt.Close()
+
+		_, err = stmt.Exec("foo", 42)
+		require.NoError(t, err)
+	})
+
+	t.Run("ERR: Err()", func(t *testing.T) {
+		db := sqlstub.New(t)
+		e := tpp.Err()
+		e.Expectorise(db.ExpectExec(sqlstub.Exact(insert)))
+
+		_, err := db.Exec(insert, "foo", 42)
+		require.True(t, errors.Is(err, e.Err))
+	})
+}
+
+func TestTx(t *testing.T) {
+	const update = "UPDATE users SET name = ?"
+
+	for _, tt := range []struct {
+		name     string
+		update   tpp.Expect
+		commit   tpp.Expect
+		rollback tpp.Expect
+		wantErr  bool
+	}{
+		{
+			name:     "OK",
+			update:   tpp.OK(sqlstub.Result{RowsAffected: 1}),
+			commit:   tpp.OK(),
+			rollback: tpp.Unexpected(),
+		},
+		{
+			name:     "ERR: update",
+			update:   tpp.Err(),
+			commit:   tpp.Unexpected(),
+			rollback: tpp.OK(),
+			wantErr:  true,
+		},
+		{
+			name:     "ERR: commit",
+			update:   tpp.OK(sqlstub.Result{RowsAffected: 1}),
+			commit:   tpp.Err(),
+			rollback: tpp.Unexpected(),
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlstub.New(t)
+			begin := tpp.OK()
+			begin.Expectorise(db.ExpectBegin())
+			tt.update.Expectorise(db.ExpectExec(sqlstub.Exact(update)))
+			tt.commit.Expectorise(db.ExpectCommit())
+			tt.rollback.Expectorise(db.ExpectRollback())
+
+			err := func() error {
+				tx, err := db.Begin()
+				if err != nil {
+					return err
+				}
+				if _, err := tx.Exec(update, "foo"); err != nil {
+					_ = tx.Rollback()
+					return err
+				}
+				return tx.Commit()
+			}()
+
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestRowsMustMatchColumns(t *testing.T) {
+	db := sqlstub.New(_t())
+	require.PanicsWithError(t, "sqlstub: row 0 has 3 value(s), but there are 2 column(s)", func() {
+		e := tpp.OK(sqlstub.Rows{
+			Columns: []string{"id", "name"},
+			Values:  [][]driver.Value{{1, "alice", "extra"}},
+		})
+		e.Expectorise(db.ExpectQuery(sqlstub.Exact(getUser)))
+	})
+}
+
+func TestFails(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		getUser tpp.Expect
+		query   string
+	}{
+		{
+			name:    "Unexpected() query is run",
+			getUser: tpp.Unexpected(),
+			query:   getUser,
+		},
+		{
+			name:    "Given() doesn't match",
+			getUser: tpp.Given(2).Return(sqlstub.Rows{}, nil),
+			query:   getUser,
+		},
+		{
+			name:  "other query",
+			query: "SELECT * FROM users",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := _t()
+			db := sqlstub.New(ft)
+			tt.getUser.Expectorise(db.ExpectQuery(sqlstub.Exact(getUser)))
+
+			_, err := db.Query(tt.query, 1)
+			require.Error(t, err)
+			require.True(t, ft.Failed())
+		})
+	}
+
+	t.Run("short row returned", func(t *testing.T) {
+		ft := _t()
+		db := sqlstub.New(ft)
+		call := db.ExpectQuery(sqlstub.Exact(getUser))
+		e := tpp.Given(1).OK(sqlstub.Rows{})
+		e.Expectorise(call)
+		call.Call.Return(sqlstub.Rows{
+			Columns: []string{"id", "name"},
+			Values:  [][]driver.Value{{1, "alice"}, {2}},
+		}, nil)
+
+		_, err := db.Query(getUser, 1)
+		require.EqualError(t, err, "sqlstub: row 1 has 1 value(s), but there are 2 column(s)")
+		require.True(t, ft.Failed())
+	})
+
+	t.Run("Begin() not expected", func(t *testing.T) {
+		ft := _t()
+		db := sqlstub.New(ft)
+
+		_, err := db.Begin()
+		require.Error(t, err)
+		require.True(t, ft.Failed())
+	})
+}
@@ -0,0 +1,329 @@
+package tpp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// StreamReturn is a channel return value which is streamed to the subject. See
+// Stream.
+type StreamReturn struct {
+	items []any
+	err   error
+}
+
+// Stream returns a value for a mock's channel return, which streams the given
+// items. For example, for a method like:
+//
+//	Subscribe(ctx context.Context, topic string) (<-chan Event, <-chan error, error)
+//
+// we might have:
+//
+//	{
+//		name:      "OK",
+//		subscribe: tpp.OK(tpp.Stream(ev1, ev2)),
+//		want:      []Event{ev1, ev2},
+//	},
+//	{
+//		name:      "ERR: stream fails",
+//		subscribe: tpp.OK(tpp.Stream(ev1).EndWith(errors.New("boom"))),
+//		want:      []Event{ev1},
+//		wantErr:   true,
+//	},
+//
+// Each call to the mock gets a fresh channel, so that one call doesn't drain
+// another's items. A goroutine sends the items on it in order, then closes it.
+//
+// If the method also returns a chan error, it's a companion channel, which is
+// fresh for each call too. It's sent the EndWith error, if any, before the
+// items channel is closed, and is closed after it.
+//
+// If the call has a context.Context arg, the goroutine stops sending once it's
+// done, and sends the context's error on the companion channel. It also stops
+// at the cleanup of the test given to Expectorise by WithTest, so that it
+// doesn't outlive the test. With either, the channel is unbuffered, so the
+// items are sent one at a time, as the subject receives them. Without either,
+// nothing could stop the goroutine, so the channel is buffered to hold all of
+// the items instead, and the goroutine finishes whether or not they're read.
+//
+// Stream is bound with mockery's RunAndReturn, so only works with mockery
+// mocks. Any chan error or error returns after the stream can be left out, and
+// are zero valued, as with OK.
+func Stream(items ...any) *StreamReturn {
+	return &StreamReturn{items: items}
+}
+
+// EndWith returns a copy of the stream which sends err on the method's
+// companion chan error once the items have all been sent.
+func (s *StreamReturn) EndWith(err error) *StreamReturn {
+	c := *s
+	c.err = err
+	return &c
+}
+
+// String describes the stream in terms of the functions used to construct it.
+func (s *StreamReturn) String() string {
+	items := make([]string, len(s.items))
+	for i, item := range s.items {
+		items[i] = fmt.Sprintf("%v", item)
+	}
+	desc := "Stream(" + strings.Join(items, ", ") + ")"
+	if s.err != nil {
+		desc += fmt.Sprintf(".EndWith(%v)", s.err)
+	}
+	return desc
+}
+
+// hasStream returns whether any of the given return args is a Stream.
+func hasStream(args []any) bool {
+	for _, arg := range args {
+		if _, ok := arg.(*StreamReturn); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorChan returns whether the given type is a channel of errors.
+func isErrorChan(t reflect.Type) bool {
+	return t.Kind() == reflect.Chan && isErrorType(t.Elem())
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// CallReturnStream is like CallReturn, but for return args which include a
+// Stream. It binds the mock with RunAndReturn, so that each call gets fresh
+// channels. If the test is given, the streams are stopped at its cleanup.
+func (rm *reflectedMockCall) CallReturnStream(
+	args []any,
+	retErr error,
+	errAt int,
+	zeroValueErrs bool,
+	t TestingT,
+) error {
+	returnType := rm.layout.returnType
+	if isVariadicAnyReturn(returnType) || rm.layout.runAndReturnIndex < 0 {
+		return errors.New("tpp: Stream needs a mock with a RunAndReturn method, like mockery's")
+	}
+
+	returnArgs := rm.returnArgs(args, retErr, errAt, zeroValueErrs)
+	for i := len(returnArgs); i < returnType.NumIn(); i++ {
+		if t := returnType.In(i); !isErrorType(t) && !isErrorChan(t) {
+			break
+		}
+		returnArgs = append(returnArgs, nil)
+	}
+
+	var (
+		stream    *StreamReturn
+		streamAt  = -1
+		errChanAt = -1
+		static    = append([]any{}, returnArgs...)
+	)
+	for i, arg := range returnArgs {
+		s, ok := arg.(*StreamReturn)
+		if !ok || i >= returnType.NumIn() {
+			continue
+		}
+		if stream != nil {
+			return errors.New("tpp: only one Stream is supported per call")
+		}
+
+		t := returnType.In(i)
+		if t.Kind() != reflect.Chan {
+			return fmt.Errorf("tpp: %s can't be returned as %s", s, t)
+		}
+		for j, item := range s.items {
+			if !canSend(item, t.Elem()) {
+				return fmt.Errorf("tpp: %s item %d (%T) can't be sent on %s", s, j, item, t)
+			}
+		}
+
+		stream, streamAt = s, i
+		static[i] = nil
+	}
+	if stream == nil {
+		// The Stream is past the end of the returns, which mustArgMatch explains.
+		rm.mustArgMatch(returnType, returnArgs)
+		return fmt.Errorf("tpp: no channel return for %s", returnArgs[len(returnArgs)-1])
+	}
+
+	for i, arg := range returnArgs {
+		if i != streamAt && arg == nil && i < returnType.NumIn() && isErrorChan(returnType.In(i)) {
+			errChanAt = i
+			break
+		}
+	}
+	if stream.err != nil {
+		if errChanAt < 0 {
+			return fmt.Errorf("tpp: %s needs a chan error return to send its error on", stream)
+		}
+		if !canSend(stream.err, returnType.In(errChanAt).Elem()) {
+			return fmt.Errorf("tpp: %s error (%T) can't be sent on %s", stream, stream.err, returnType.In(errChanAt))
+		}
+	}
+
+	rm.mustArgMatch(returnType, static)
+
+	outs, err := toReflectValues(static, returnType)
+	if err != nil {
+		return fmt.Errorf("toReflectValues failed to transform return values: %s", err)
+	}
+
+	streams := &streamGroup{done: make(chan struct{})}
+	if t != nil {
+		t.Cleanup(streams.close)
+	}
+
+	return rm.CallRunAndReturn(func(fnType reflect.Type) (reflect.Value, error) {
+		return reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+			out := append([]reflect.Value{}, outs...)
+
+			ctx := contextArg(in)
+			buffer := 0
+			if t == nil && (ctx == nil || ctx.Done() == nil) {
+				// Nothing can stop the goroutine, so it mustn't block.
+				buffer = len(stream.items)
+			}
+
+			ch := makeChan(returnType.In(streamAt), buffer)
+			out[streamAt] = ch.Convert(returnType.In(streamAt))
+
+			var errCh reflect.Value
+			if errChanAt >= 0 {
+				errCh = makeChan(returnType.In(errChanAt), 1)
+				out[errChanAt] = errCh.Convert(returnType.In(errChanAt))
+			}
+
+			streams.start(func(done <-chan struct{}) {
+				stream.send(ctx, done, ch, errCh)
+			})
+			return out
+		}), nil
+	})
+}
+
+// streamGroup tracks the goroutines sending the streams of an Expect, so that
+// they can be stopped, and waited for, at the test's cleanup.
+type streamGroup struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	done   chan struct{}
+	closed bool
+}
+
+// start runs send in a goroutine, or straight away if the group is closed, in
+// which case done is already closed too.
+func (g *streamGroup) start(send func(done <-chan struct{})) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		send(g.done)
+		return
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		send(g.done)
+	}()
+}
+
+// close stops the group's goroutines and waits for them to finish.
+func (g *streamGroup) close() {
+	g.mu.Lock()
+	if !g.closed {
+		g.closed = true
+		close(g.done)
+	}
+	g.mu.Unlock()
+
+	g.wg.Wait()
+}
+
+// send sends the stream's items on ch, until ctx or done is done, then ends
+// the stream.
+func (s *StreamReturn) send(ctx context.Context, done <-chan struct{}, ch, errCh reflect.Value) {
+	defer func() {
+		ch.Close()
+		if errCh.IsValid() {
+			errCh.Close()
+		}
+	}()
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+	}
+	if ctx != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	}
+
+	for _, item := range s.items {
+		cases[0].Send = sendValue(item, ch.Type().Elem())
+		switch chosen, _, _ := reflect.Select(cases); chosen {
+		case 1:
+			return
+		case 2:
+			s.end(errCh, ctx.Err())
+			return
+		}
+	}
+
+	s.end(errCh, s.err)
+}
+
+// end sends err on the companion errCh, if there is one and err fits.
+func (s *StreamReturn) end(errCh reflect.Value, err error) {
+	if err != nil && errCh.IsValid() && canSend(err, errCh.Type().Elem()) {
+		errCh.Send(sendValue(err, errCh.Type().Elem()))
+	}
+}
+
+// makeChan makes a bidirectional channel with the same element type as t.
+func makeChan(t reflect.Type, buffer int) reflect.Value {
+	return reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.Elem()), buffer)
+}
+
+// canSend returns whether v can be sent on a channel of elem.
+func canSend(v any, elem reflect.Type) bool {
+	if v == nil {
+		switch elem.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+			return true
+		}
+		return false
+	}
+	return reflect.TypeOf(v).AssignableTo(elem)
+}
+
+// sendValue returns v as a value to send on a channel of elem. It must be
+// canSend.
+func sendValue(v any, elem reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(elem)
+	}
+	return reflect.ValueOf(v)
+}
+
+// contextArg returns the first non-nil context.Context in the call's args, or
+// nil if there isn't one.
+func contextArg(in []reflect.Value) context.Context {
+	for _, v := range in {
+		if !v.Type().Implements(contextType) {
+			continue
+		}
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			continue
+		}
+		ctx, _ := v.Interface().(context.Context)
+		return ctx
+	}
+	return nil
+}
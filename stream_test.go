@@ -0,0 +1,161 @@
+package tpp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+// collect is a subject which reads a topic's stream to the end.
+func collect(ctx context.Context, thing testdata.StreamyThing, topic string) ([]int, error) {
+	ch, errCh, err := thing.DoThing(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	return got, <-errCh
+}
+
+func TestStream(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	for _, tt := range []struct {
+		name    string
+		doThing tpp.Expect
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			doThing: tpp.OK(tpp.Stream(1, 2, 3)),
+			want:    []int{1, 2, 3},
+		},
+		{
+			name:    "OK: empty",
+			doThing: tpp.OK(tpp.Stream()),
+		},
+		{
+			name:    "OK: companion given",
+			doThing: tpp.OK(tpp.Stream(1), nil, nil),
+			want:    []int{1},
+		},
+		{
+			name:    "ERR: stream ends with error",
+			doThing: tpp.OK(tpp.Stream(1, 2).EndWith(errBoom)),
+			want:    []int{1, 2},
+			wantErr: errBoom,
+		},
+		{
+			name:    "ERR: call fails",
+			doThing: tpp.ErrWith(errBoom),
+			wantErr: errBoom,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			thing := testdata.NewMockStreamyThing(t)
+			tt.doThing.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+			got, err := collect(context.Background(), thing, "topic")
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("fresh channel per call", func(t *testing.T) {
+		thing := testdata.NewMockStreamyThing(t)
+		e := tpp.OK(tpp.Stream(1, 2)).Times(2)
+		e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+		for i := 0; i < 2; i++ {
+			got, err := collect(context.Background(), thing, "topic")
+			require.NoError(t, err)
+			require.Equal(t, []int{1, 2}, got)
+		}
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		thing := testdata.NewMockStreamyThing(t)
+		e := tpp.OK(tpp.Stream(1, 2, 3))
+		e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, errCh, err := thing.DoThing(ctx, "topic")
+		require.NoError(t, err)
+		require.Equal(t, 1, <-ch)
+
+		cancel()
+		require.ErrorIs(t, <-errCh, context.Canceled)
+		_, ok := <-ch
+		require.False(t, ok)
+	})
+
+	t.Run("closes at cleanup", func(t *testing.T) {
+		var ch <-chan int
+		t.Run("subtest", func(t *testing.T) {
+			thing := testdata.NewMockStreamyThing(t)
+			e := tpp.OK(tpp.Stream(1, 2, 3))
+			e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()), tpp.WithTest(t))
+
+			var err error
+			ch, _, err = thing.DoThing(context.Background(), "topic")
+			require.NoError(t, err)
+		})
+
+		_, ok := <-ch
+		require.False(t, ok)
+	})
+
+	t.Run("buffered without context or test", func(t *testing.T) {
+		thing := testdata.NewMockStreamyThing(t)
+		e := tpp.OK(tpp.Stream(1, 2, 3))
+		e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+		ch, _, err := thing.DoThing(context.Background(), "topic")
+		require.NoError(t, err)
+		require.Equal(t, 3, cap(ch))
+	})
+
+	t.Run("EndWith doesn't change the original", func(t *testing.T) {
+		stream := tpp.Stream(1)
+		ended := stream.EndWith(errBoom)
+		require.Equal(t, "Stream(1)", stream.String())
+		require.Equal(t, "Stream(1).EndWith(boom)", ended.String())
+	})
+
+	t.Run("panics on non-channel return", func(t *testing.T) {
+		thing := testdata.NewMockIntyThing(_t())
+
+		e := tpp.OK(tpp.Stream(1))
+		require.PanicsWithError(t, "tpp: Stream(1) can't be returned as int", func() {
+			e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+		})
+	})
+
+	t.Run("panics on item of wrong type", func(t *testing.T) {
+		thing := testdata.NewMockStreamyThing(_t())
+
+		e := tpp.OK(tpp.Stream(1, "two"))
+		require.PanicsWithError(t, "tpp: Stream(1, two) item 1 (string) can't be sent on <-chan int", func() {
+			e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+		})
+	})
+
+	t.Run("panics on EndWith without companion", func(t *testing.T) {
+		thing := testdata.NewMockStreamyThing(_t())
+
+		ch := make(chan error)
+		e := tpp.OK(tpp.Stream(1).EndWith(errBoom), (<-chan error)(ch))
+		require.PanicsWithError(t, "tpp: Stream(1).EndWith(boom) needs a chan error return to send its error on", func() {
+			e.Expectorise(thing.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+		})
+	})
+}
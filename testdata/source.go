@@ -1,6 +1,9 @@
 package testdata
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // The generated mockery mocks in this package are from the following definitions:
 
@@ -32,3 +35,62 @@ type SliceyThing interface {
 type FuncyThing interface {
 	DoThing(func(int) int) func(int) int
 }
+
+type CodedError interface {
+	error
+	Code() int
+}
+
+type CodedErrorThing interface {
+	DoThing(int) (int, CodedError)
+}
+
+type ConcreteError struct {
+	Msg string
+}
+
+type ConcreteErrorThing interface {
+	DoThing(int) (int, *ConcreteError)
+}
+
+type MultiErrorThing interface {
+	DoThing(int) (int, error, error)
+}
+
+type PairyThing interface {
+	DoThing(int) (int, string, error)
+}
+
+// These implement the error types above, for use in tests.
+
+func (e *ConcreteError) Error() string {
+	return e.Msg
+}
+
+type StatusError struct {
+	Status int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d", e.Status)
+}
+
+func (e *StatusError) Code() int {
+	return e.Status
+}
+
+// MockVariadicThing is generated with mockery's default unroll-variadic: true,
+// and MockPackedVariadicThing with unroll-variadic: false.
+type VariadicThing interface {
+	DoThing(ctx context.Context, keys ...string) error
+}
+
+type Repo[T any] interface {
+	Get(ctx context.Context, id string) (T, error)
+	Put(ctx context.Context, id string, v T) error
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+type StreamyThing interface {
+	DoThing(ctx context.Context, topic string) (<-chan int, <-chan error, error)
+}
@@ -39,6 +39,8 @@ Here's a template for writing such tests:
 */
 
 import (
+	"reflect"
+
 	"github.com/pkg/errors"
 
 	testifymock "github.com/stretchr/testify/mock"
@@ -67,18 +69,35 @@ func OK(returns ...any) Expect {
 }
 
 // Err returns an Expect with a generic test error.
+//
+// Each call to Err returns a distinct error, labelled with the call site, so
+// that tests can check which Expect an error came from. See RequireErrFrom.
 func Err() Expect {
+	return errExpect(1)
+}
+
+// ErrWith returns an Expect with the given error.
+//
+// The error will be returned in the last of the mock's return values which can
+// hold it. This means that mocks which return custom error types, such as
+// *MyError, need to be given an error of that type.
+func ErrWith(e error) Expect {
 	return Expect{
 		Expected: ptr(true),
-		Err:      errDefault,
+		Err:      e,
 	}
 }
 
-// ErrWith returns an Expect with the given error.
-func ErrWith(e error) Expect {
+// ErrAt returns an Expect with the given error, which will be returned at index
+// i of the mock's return values.
+//
+// This is only needed for mocks with more than one error return; otherwise,
+// ErrWith will put the error in the right place.
+func ErrAt(i int, e error) Expect {
 	return Expect{
 		Expected: ptr(true),
 		Err:      e,
+		errAt:    ptr(i),
 	}
 }
 
@@ -90,6 +109,13 @@ func Unexpected() Expect {
 }
 
 // Given starts a builder with args which will ultimately configure an Expect.
+//
+// The builder is finished by any of the usual ways of making an Expect, e.g.:
+//
+//	tpp.Given(1, 2).Return(3, nil)
+//	tpp.Given(1, 2).OK(3)
+//	tpp.Given(1, 2).Err()
+//	tpp.Given(1, 2).Times(2).ErrWith(errFoo)
 func Given(args ...any) *callBuilder {
 	return &callBuilder{
 		args: args,
@@ -97,17 +123,66 @@ func Given(args ...any) *callBuilder {
 }
 
 type callBuilder struct {
-	args []any
+	args   []any
+	nTimes int
+}
+
+// Times returns a copy of the builder, for an Expect which will return the
+// given number of times. See Expect.Times.
+func (c *callBuilder) Times(n int) *callBuilder {
+	b := *c
+	b.nTimes = n
+	return &b
+}
+
+// build returns the Expect with the builder's args and Times.
+func (c *callBuilder) build(e Expect) Expect {
+	e.argReplacements = c.args
+	e.nTimes = c.nTimes
+	return e
 }
 
 // Return returns an Expect with the given returns and args from Given().
 func (c *callBuilder) Return(returns ...any) Expect {
-	return Expect{
-		Expected:        ptr(true),
-		argReplacements: c.args,
-		Return:          returns,
-		exactReturn:     true,
-	}
+	return c.build(Return(returns...))
+}
+
+// OK returns an Expect with the given returns and no error, and args from
+// Given(). See OK.
+func (c *callBuilder) OK(returns ...any) Expect {
+	return c.build(OK(returns...))
+}
+
+// Err returns an Expect with a generic test error and args from Given(). See
+// Err.
+func (c *callBuilder) Err() Expect {
+	return c.build(errExpect(1))
+}
+
+// ErrWith returns an Expect with the given error and args from Given(). See
+// ErrWith.
+func (c *callBuilder) ErrWith(e error) Expect {
+	return c.build(ErrWith(e))
+}
+
+// ErrAt returns an Expect with the given error at index i of the returns, and
+// args from Given(). See ErrAt.
+func (c *callBuilder) ErrAt(i int, e error) Expect {
+	return c.build(ErrAt(i, e))
+}
+
+// Maybe returns an Expect which may or may not be called with the args from
+// Given(), and which returns the given returns if it is.
+func (c *callBuilder) Maybe(returns ...any) Expect {
+	return c.build(Expect{Return: returns})
+}
+
+// Unexpected returns an Expect which is unexpected, with args from Given().
+//
+// Note that Expectorise unsets an unexpected mock call entirely, so the args
+// only serve to document the call.
+func (c *callBuilder) Unexpected() Expect {
+	return c.build(Unexpected())
 }
 
 // Arg represents an argument placeholder to be used with tpp.Given(). This can
@@ -172,6 +247,10 @@ type Expect struct {
 	// This is separated out from `Return` for convenience and readability.
 	Err error
 
+	// errAt is the index of the return value which Err should be returned in.
+	// If nil, it will be returned in the last return value which can hold it.
+	errAt *int
+
 	// argReplacements are optional arguments which we will use to replace any
 	// tpp.Arg values in the mock.Arguments. These will *only* be added to the mock
 	// call if its arguments are specified as tpp.Arg().
@@ -183,6 +262,17 @@ type Expect struct {
 	// of times.
 	nTimes int
 
+	// callRange is the range of times the mock must be called, if given by
+	// AtLeast, AtMost, Between or AnyTimes.
+	callRange *callRange
+
+	// fuzzSeed is set by FuzzExpects, and seeds the return values which are
+	// generated once the mock's return types are known.
+	fuzzSeed *int64
+
+	// effects are side effects of the mock call. See Then.
+	effects []Effect
+
 	// exactReturn determines that the mock should be configured to return exactly
 	// what is specified by Return, and not have errors zero-valued out if
 	// unspecified. This is here temporarily to support Return() and
@@ -199,18 +289,16 @@ type Expect struct {
 // is a factory which returns some also-mocked object constructed within the
 // meta-test.
 func (e *Expect) Injecting(ret any) *Expect {
-	return &Expect{
-		Expected:    e.Expected,
-		Return:      append(e.Return, ret),
-		Err:         e.Err,
-		exactReturn: e.exactReturn,
-	}
+	injected := *e
+	injected.Return = append(append([]any{}, e.Return...), ret)
+	return &injected
 }
 
-// Times indicates that the mock should only return the indicated number of times.
-// of times.
+// Times indicates that the mock should only return the indicated number of
+// times. This replaces any range given by AtLeast, AtMost, Between or AnyTimes.
 func (e Expect) Times(n int) Expect {
 	e.nTimes = n
+	e.callRange = nil
 	return e
 }
 
@@ -222,6 +310,13 @@ func (e Expect) Once() Expect {
 // expectoriseOptions is used to configure Expectorise and ExpectoriseMulti.
 type expectoriseOptions struct {
 	defaultReturns []any
+	packedVariadic bool
+	clock          *Clock
+	test           TestingT
+
+	// runAndReturn makes a func for the mock's RunAndReturn, given its type.
+	// It's set by StatefulFake.
+	runAndReturn func(fnType reflect.Type) (reflect.Value, error)
 }
 
 type ExpectoriseOption func(*expectoriseOptions)
@@ -229,13 +324,50 @@ type ExpectoriseOption func(*expectoriseOptions)
 // WithDefaultReturns sets default returns to be used when configuring the mock.
 //
 // These will be provided instead of zero-value returns where returns are not
-// otherwise provided by the Expect.
+// otherwise provided by the Expect. They take precedence over any registered
+// with RegisterDefaults.
 func WithDefaultReturns(returns ...any) ExpectoriseOption {
 	return func(opt *expectoriseOptions) {
 		opt.defaultReturns = returns
 	}
 }
 
+// WithPackedVariadic indicates that the mock was generated by mockery with
+// unroll-variadic: false. Such mocks pass their variadic args to testify as a
+// single slice, rather than as separate args, so Expectorise needs to pack the
+// args given by tpp.Given() to match.
+//
+// A single variadic arg given to tpp.Given() which can't be one of the variadic
+// args, such as a []string or a mock.MatchedBy for a mock taking ...string, is
+// taken to stand for all of them.
+func WithPackedVariadic() ExpectoriseOption {
+	return func(opt *expectoriseOptions) {
+		opt.packedVariadic = true
+	}
+}
+
+// WithTest gives Expectorise the test which the mock call is for, which is
+// usually the test the mock was created with. Mocks don't give their test
+// back, so it must be passed explicitly for the features which need it:
+//
+//   - AtLeast, AtMost and Between, which fail it rather than the test they
+//     were given, and check the calls at its cleanup.
+//   - RegisterTestDefaults, whose defaults only apply to Expectorise calls
+//     given the test, or one of its subtests.
+//   - Stream, which stops sending at the test's cleanup.
+//   - JUnit Reports, which list the calls set up for a failed case.
+func WithTest(t TestingT) ExpectoriseOption {
+	return func(opt *expectoriseOptions) {
+		opt.test = t
+	}
+}
+
+// TestingT is the subset of testing.T which tpp needs.
+type TestingT interface {
+	testifymock.TestingT
+	Cleanup(func())
+}
+
 // MockCall represents a Mockery mock.
 type MockCall interface {
 	Maybe() *testifymock.Call
@@ -253,7 +385,8 @@ type MockCall interface {
 // its return values, and whether it should return an error.
 //
 // Iff any of the arguments to the mock are tpp.Arg(), they will be replaced by
-// the arguments in Expect.Args.
+// the arguments in Expect.Args. For variadic methods, a single tpp.Arg() in
+// place of the variadic args stands for all of them; see tpp.Rest().
 //
 // If Expect.Expected is true the mock must be called. If false, the mock must
 // not be called. If nil, the mock may be called.
@@ -263,18 +396,37 @@ type MockCall interface {
 // ensure that one of these zero values is set to a non-nil error.
 //
 // If Expect.Return is non-nil, Expectorise will configure the mock to return
-// Expect.Return. If Expect.Err is also set, Expectorise will append a non-nil
+// Expect.Return. If Expect.Err is also set, Expectorise will add a non-nil
 // error to the returned values.
-func (e *Expect) Expectorise(mock MockCall, options ...ExpectoriseOption) {
+//
+// Error values are any return values whose type implements error, so this
+// includes custom error interfaces and concrete error types. Where there's more
+// than one, Expect.Err is returned in the last one which can hold it (or the
+// one specified by ErrAt) and the others are zero valued.
+//
+// It returns the Expectation it configured, which can be passed to WaitFor.
+// The Expect itself isn't changed, so it can be shared between test cases.
+//
+// Expectorise isn't safe to call while the mock is in use by other goroutines,
+// e.g. parallel subtests sharing a mock, or a subject which is already calling
+// it. It changes the mock call's args, and the mock's expected calls, in place,
+// and testify doesn't give us a way to take its lock while we do.
+func (e *Expect) Expectorise(mock MockCall, options ...ExpectoriseOption) *Expectation {
 	// Parse options
 	var opts expectoriseOptions
 	for _, o := range options {
 		o(&opts)
 	}
 
+	x := &Expectation{expect: *e}
+	if junitActive() {
+		x.site = callerSite()
+		recordJUnitExpectation(opts.testName(), x)
+	}
+
 	if e.Expected != nil && !*e.Expected {
-		unsetMock(mock)
-		return
+		x.call = unsetMock(mock)
+		return x
 	}
 
 	if e.Expected == nil {
@@ -289,6 +441,7 @@ func (e *Expect) Expectorise(mock MockCall, options ...ExpectoriseOption) {
 	if err != nil {
 		panic(err)
 	}
+	x.call = rmock.call
 
 	// Replace any args that have been specified with tpp.Arg() with the args
 	// specified on the Expect.
@@ -296,43 +449,74 @@ func (e *Expect) Expectorise(mock MockCall, options ...ExpectoriseOption) {
 	if err != nil {
 		panic(err)
 	}
-	var newargs []any
-	for i, arg := range args {
-		if _, ok := arg.(templateArg); ok {
-			if i >= len(e.argReplacements) {
-				// We've ran out of supplied args. This happens commonly, since the
-				// Expect might be empty or an error, but the test-body specifies
-				// a tpp.Arg() for the mock arguments. Fall back to mock.Anything.
-				newargs = append(newargs, testifymock.Anything)
-			} else {
-				newargs = append(newargs, e.argReplacements[i])
-				i++
-			}
-		} else {
-			newargs = append(newargs, arg)
-		}
+	rmock.SetArguments(e.substituteArgs(args, rmock, opts.packedVariadic || isPackedVariadic(mock)))
+
+	errAt := -1
+	if e.errAt != nil {
+		errAt = *e.errAt
 	}
-	rmock.SetArguments(newargs)
 
 	switch {
-	case e.Return != nil:
-		err := rmock.CallReturn(e.Return, e.Err, !e.exactReturn)
+	case hasStream(e.Return):
+		err := rmock.CallReturnStream(e.Return, e.Err, errAt, !e.exactReturn, opts.test)
 		if err != nil {
 			panic(err)
 		}
 
-	case e.Err != nil:
-		rmock.CallReturnEmpty(e.Err)
+	case e.fuzzSeed != nil && !isVariadicAnyReturn(rmock.layout.returnType):
+		err := rmock.CallReturn(fuzzReturns(rmock.layout, *e.fuzzSeed, e.Return), nil, -1, true)
+		if err != nil {
+			panic(err)
+		}
 
-	case opts.defaultReturns != nil:
-		err := rmock.CallReturn(opts.defaultReturns, nil, false)
+	case e.Return != nil:
+		err := rmock.CallReturn(e.Return, e.Err, errAt, !e.exactReturn)
 		if err != nil {
 			panic(err)
 		}
 
+	case e.Err != nil:
+		rmock.CallReturnEmpty(e.Err, errAt)
+
 	default:
-		rmock.CallReturnEmpty(nil)
+		if err := opts.returnDefaults(mock, rmock); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := e.installEffects(rmock.call, &opts); err != nil {
+		panic(err)
+	}
+
+	switch {
+	case e.callRange != nil:
+		if err := x.installRange(opts.test); err != nil {
+			panic(err)
+		}
+
+	case e.Expected != nil && *e.Expected:
+		x.countCalls()
 	}
+
+	return x
+}
+
+// Expectation is a mock call configured by Expectorise. It's kept apart from
+// the Expect it was configured from, which is usually test table data and may
+// be shared between cases, so that each call has its own.
+type Expectation struct {
+	expect Expect
+
+	// call is the testify call which was configured, if the mock has one, so
+	// that WaitFor can tell when it's been made.
+	call *testifymock.Call
+
+	// counter counts the calls routed to call, if it must be called.
+	counter *callCounter
+
+	// site is where the Expectation was configured, for JUnit reports. It's
+	// only recorded while one is being written. See junitActive.
+	site string
 }
 
 // -----------------------------------------------------------------------------
@@ -361,8 +545,11 @@ func (e *Expect) Expectorise(mock MockCall, options ...ExpectoriseOption) {
 //
 // An empty slice will result in the mock call being unexpected.
 //
+// It returns the Expectations it configured, in order, which can be passed to
+// WaitFor. A nil slice returns none, since the call may or may not be made.
+//
 // For more info, see Expect.Expectorise.
-func ExpectoriseMulti(ee []Expect, callFn func() MockCall, options ...ExpectoriseOption) {
+func ExpectoriseMulti(ee []Expect, callFn func() MockCall, options ...ExpectoriseOption) []*Expectation {
 	// Parse options
 	var opts expectoriseOptions
 	for _, o := range options {
@@ -385,48 +572,55 @@ func ExpectoriseMulti(ee []Expect, callFn func() MockCall, options ...Expectoris
 		if err != nil {
 			panic(err)
 		}
-		var newargs []any
-		for _, arg := range args {
-			if _, ok := arg.(templateArg); ok {
-				newargs = append(newargs, testifymock.Anything)
-			} else {
-				newargs = append(newargs, arg)
-			}
-		}
-		rmock.SetArguments(newargs)
-
-		// Return either the specified default, or empty.
-		if opts.defaultReturns != nil {
-			err := rmock.CallReturn(opts.defaultReturns, nil, false)
-			if err != nil {
-				panic(err)
-			}
-		} else {
-			rmock.CallReturnEmpty(nil)
+		rmock.SetArguments(substitutePositional(args, nil))
+
+		// Return either the default, or empty.
+		if err := opts.returnDefaults(call, rmock); err != nil {
+			panic(err)
 		}
-		return
+		return nil
 	}
 
+	var xs []*Expectation
 	for _, e := range ee {
 		e := e
 		call := callFn()
-		e.Expectorise(call)
+		xs = append(xs, e.Expectorise(call, options...))
 	}
+	return xs
 }
 
 // -----------------------------------------------------------------------------
 // Unexported Helpers ----------------------------------------------------------
 // -----------------------------------------------------------------------------
 
+// returnDefaults sets up the mock call's returns when they're not given by the
+// Expect: from a StatefulFake, or else the default returns, or else zero
+// values.
+func (opts *expectoriseOptions) returnDefaults(mock MockCall, rmock *reflectedMockCall) error {
+	if opts.runAndReturn != nil {
+		return rmock.CallRunAndReturn(opts.runAndReturn)
+	}
+
+	if defaults := opts.defaultsFor(mock, rmock.call); defaults != nil {
+		return rmock.CallReturn(defaults, nil, -1, false)
+	}
+
+	rmock.CallReturnEmpty(nil, -1)
+	return nil
+}
+
 // unsetMock unsets a mock. This is necessary because testify's mock.Call.Unset()
-// does not gracefully handle the case where we have an argument matcher.
-func unsetMock(mock MockCall) {
+// does not gracefully handle the case where we have an argument matcher. It
+// returns the unset call, if the mock has one.
+func unsetMock(mock MockCall) *testifymock.Call {
 	// mock may be a type that wraps a testify mock.Call, we can use Maybe to extract it, and then unset.
 	if call := mock.Maybe(); call != nil {
 		safeUnsetCall(call)
-	} else {
-		mock.Unset()
+		return call
 	}
+	mock.Unset()
+	return nil
 }
 
 // safeUnsetCall safely unsets a mock call from its parent mock object.
@@ -450,6 +644,47 @@ func safeUnsetCall(call *testifymock.Call) {
 	}
 }
 
+// substituteArgs returns the mock's args with any tpp.Arg()s replaced by the
+// Expect's argReplacements. If there aren't enough of those, the tpp.Arg()s
+// are replaced with mock.Anything.
+func (e *Expect) substituteArgs(args []any, rmock *reflectedMockCall, packed bool) []any {
+	// If the mock call is variadic, and its variadic args were given as a single
+	// tpp.Arg(), that stands for however many variadic args the Expect has.
+	fixed, elem, ok := rmock.VariadicArgs()
+	if ok && e.argReplacements != nil && len(args) == fixed+1 && isTemplateArg(args[fixed]) {
+		head := substitutePositional(args[:fixed], e.argReplacements)
+		tail := e.argReplacements[min(fixed, len(e.argReplacements)):]
+		return append(head, variadicTail(tail, elem, packed)...)
+	}
+
+	return substitutePositional(args, e.argReplacements)
+}
+
+// substitutePositional replaces the tpp.Arg()s in args with the replacement at
+// the same index, or mock.Anything if there isn't one.
+func substitutePositional(args, replacements []any) []any {
+	var newargs []any
+	for i, arg := range args {
+		switch {
+		case !isTemplateArg(arg):
+			newargs = append(newargs, arg)
+		case i < len(replacements):
+			newargs = append(newargs, replacements[i])
+		default:
+			// We've ran out of supplied args. This happens commonly, since the
+			// Expect might be empty or an error, but the test-body specifies
+			// a tpp.Arg() for the mock arguments. Fall back to mock.Anything.
+			newargs = append(newargs, testifymock.Anything)
+		}
+	}
+	return newargs
+}
+
+func isTemplateArg(arg any) bool {
+	_, ok := arg.(templateArg)
+	return ok
+}
+
 var errDefault = errors.New("ERROR")
 
 func ptr[T any](t T) *T {
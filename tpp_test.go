@@ -36,6 +36,7 @@ var testStructures = []struct {
 	returnTypes     []string
 	defaultReturns  []any
 	examples        []exampleCall
+	customErr       error
 	expectoriseCall func(
 		e tpp.Expect,
 		args []any,
@@ -240,6 +241,314 @@ var testStructures = []struct {
 			return &mock.Mock
 		},
 	},
+	{
+		name:           "func(int) (int, CodedError)",
+		argTypes:       []string{"int"},
+		defaultArgs:    []any{1},
+		returnTypes:    []string{"int", "CodedError"},
+		defaultReturns: []any{1, testdata.CodedError(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{1},
+				returns: []any{123, testdata.CodedError(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{1},
+				returns: []any{0, testdata.CodedError(&testdata.StatusError{Status: 500})},
+			},
+		},
+		customErr: &testdata.StatusError{Status: 500},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 1, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockCodedErrorThing(_t())
+			call := mock.EXPECT().DoThing(args[0])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 1, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockCodedErrorThing(_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().DoThing(args[0])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "func(int) (int, *ConcreteError)",
+		argTypes:       []string{"int"},
+		defaultArgs:    []any{1},
+		returnTypes:    []string{"int", "*ConcreteError"},
+		defaultReturns: []any{1, (*testdata.ConcreteError)(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{1},
+				returns: []any{123, (*testdata.ConcreteError)(nil)},
+			},
+		},
+		customErr: &testdata.ConcreteError{Msg: "oops"},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 1, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockConcreteErrorThing(_t())
+			call := mock.EXPECT().DoThing(args[0])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 1, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockConcreteErrorThing(_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().DoThing(args[0])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "func(int) (int, error, error)",
+		argTypes:       []string{"int"},
+		defaultArgs:    []any{1},
+		returnTypes:    []string{"int", "error", "error"},
+		defaultReturns: []any{1, error(nil), error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{1},
+				returns: []any{123, error(nil), error(nil)},
+			},
+			{
+				name:    "first err",
+				args:    []any{1},
+				returns: []any{0, errTest, error(nil)},
+			},
+			{
+				name:    "second err",
+				args:    []any{1},
+				returns: []any{0, error(nil), errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 1, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockMultiErrorThing(_t())
+			call := mock.EXPECT().DoThing(args[0])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 1, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockMultiErrorThing(_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().DoThing(args[0])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "func(context.Context, ...string) error",
+		argTypes:       []string{"Context", "string", "string"},
+		defaultArgs:    []any{context.Background(), "a", "b"},
+		returnTypes:    []string{"error"},
+		defaultReturns: []any{error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), "a", "b"},
+				returns: []any{error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), "c", "d"},
+				returns: []any{errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 3, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockVariadicThing(_t())
+			call := mock.EXPECT().DoThing(args[0], args[1:]...)
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 3, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockVariadicThing(_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().DoThing(args[0], args[1:]...)
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "func(context.Context, ...string) error (packed)",
+		argTypes:       []string{"Context", "[]string"},
+		defaultArgs:    []any{context.Background(), []string{"a", "b"}},
+		returnTypes:    []string{"error"},
+		defaultReturns: []any{error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), []string{"a", "b"}},
+				returns: []any{error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), []string{"c", "d"}},
+				returns: []any{errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 2, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockPackedVariadicThing(_t())
+			call := mock.EXPECT().DoThing(args[0], args[1])
+			expect.Expectorise(call, append(opts, tpp.WithPackedVariadic())...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 2, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockPackedVariadicThing(_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().DoThing(args[0], args[1])
+			}, append(opts, tpp.WithPackedVariadic())...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "Repo[*Struct].Get",
+		argTypes:       []string{"Context", "string"},
+		defaultArgs:    []any{context.Background(), "id"},
+		returnTypes:    []string{"*Struct", "error"},
+		defaultReturns: []any{&testdata.Struct{A: 1, B: 2}, error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), "id"},
+				returns: []any{&testdata.Struct{A: 1, B: 2}, error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), "id"},
+				returns: []any{(*testdata.Struct)(nil), errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 2, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockRepo[*testdata.Struct](_t())
+			call := mock.EXPECT().Get(args[0], args[1])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 2, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockRepo[*testdata.Struct](_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().Get(args[0], args[1])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "Repo[int].Get",
+		argTypes:       []string{"Context", "string"},
+		defaultArgs:    []any{context.Background(), "id"},
+		returnTypes:    []string{"int", "error"},
+		defaultReturns: []any{1, error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), "id"},
+				returns: []any{123, error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), "id"},
+				returns: []any{0, errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 2, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockRepo[int](_t())
+			call := mock.EXPECT().Get(args[0], args[1])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 2, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockRepo[int](_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().Get(args[0], args[1])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "Repo[any].Get",
+		argTypes:       []string{"Context", "string"},
+		defaultArgs:    []any{context.Background(), "id"},
+		returnTypes:    []string{"any", "error"},
+		defaultReturns: []any{"foo", error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), "id"},
+				returns: []any{"foo", error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), "id"},
+				returns: []any{nil, errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 2, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockRepo[any](_t())
+			call := mock.EXPECT().Get(args[0], args[1])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 2, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockRepo[any](_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().Get(args[0], args[1])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
+	{
+		name:           "Repo[Struct].Put",
+		argTypes:       []string{"Context", "string", "Struct"},
+		defaultArgs:    []any{context.Background(), "id", testdata.Struct{A: 1, B: 2}},
+		returnTypes:    []string{"error"},
+		defaultReturns: []any{error(nil)},
+		examples: []exampleCall{
+			{
+				name:    "ok",
+				args:    []any{context.Background(), "id", testdata.Struct{A: 1, B: 2}},
+				returns: []any{error(nil)},
+			},
+			{
+				name:    "err",
+				args:    []any{context.Background(), "id", testdata.Struct{A: 3, B: 4}},
+				returns: []any{errTest},
+			},
+		},
+		expectoriseCall: func(expect tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) (*testifymock.Call, *testifymock.Mock) {
+			must(len(args) == 3, "expectoriseCall: wrong arg count")
+			mock := testdata.NewMockRepo[testdata.Struct](_t())
+			call := mock.EXPECT().Put(args[0], args[1], args[2])
+			expect.Expectorise(call, opts...)
+			return call.Call, &mock.Mock
+		},
+		expectoriseMulti: func(expects []tpp.Expect, args []any, opts ...tpp.ExpectoriseOption) *testifymock.Mock {
+			must(len(args) == 3, "expectoriseMulti: wrong arg count")
+			mock := testdata.NewMockRepo[testdata.Struct](_t())
+			tpp.ExpectoriseMulti(expects, func() tpp.MockCall {
+				return mock.EXPECT().Put(args[0], args[1], args[2])
+			}, opts...)
+			return &mock.Mock
+		},
+	},
 	{
 		name: "Testify mock",
 		// These tests check the behaviour of Expectorise when it's passed a "bare"
@@ -358,8 +667,8 @@ func TestExpect(t *testing.T) {
 						call, _ := tt.expectoriseCall(tpp.Err(), tt.defaultArgs)
 
 						require.Len(t, call.ReturnArguments, len(tt.returnTypes))
-						for i, ret := range tt.returnTypes {
-							if ret == "error" {
+						for i := range tt.returnTypes {
+							if i == errIndex(tt.returnTypes) {
 								_, ok := call.ReturnArguments[i].(error)
 								require.True(t, ok)
 							} else {
@@ -376,8 +685,8 @@ func TestExpect(t *testing.T) {
 					t.Run("ErrWith() sets up err return", func(t *testing.T) {
 						call, _ := tt.expectoriseCall(tpp.ErrWith(errTest), tt.defaultArgs)
 
-						for i, ret := range tt.returnTypes {
-							if ret == "error" {
+						for i := range tt.returnTypes {
+							if i == errIndex(tt.returnTypes) {
 								retErr, ok := call.ReturnArguments[i].(error)
 								require.True(t, ok)
 								require.Equal(t, errTest, retErr)
@@ -393,6 +702,27 @@ func TestExpect(t *testing.T) {
 					})
 				}
 
+				if tt.customErr != nil {
+					t.Run("ErrWith() sets up custom err return", func(t *testing.T) {
+						call, _ := tt.expectoriseCall(tpp.ErrWith(tt.customErr), tt.defaultArgs)
+
+						require.Len(t, call.ReturnArguments, len(tt.returnTypes))
+						for i := range tt.returnTypes {
+							if i == len(tt.returnTypes)-1 {
+								require.Equal(t, tt.customErr, call.ReturnArguments[i])
+							} else {
+								require.Empty(t, call.ReturnArguments[i])
+							}
+						}
+					})
+
+					t.Run("Err() panics for custom err type", func(t *testing.T) {
+						require.Panics(t, func() {
+							tt.expectoriseCall(tpp.Err(), tt.defaultArgs)
+						})
+					})
+				}
+
 				for _, example := range tt.examples {
 					t.Run("Given().Return() sets up args and return", func(t *testing.T) {
 						expect := tpp.Given(example.args...).Return(example.returns...)
@@ -457,6 +787,75 @@ func TestExpect(t *testing.T) {
 				}
 			}
 
+			for _, example := range tt.examples {
+				returnsWithoutErrTypes, returnsWithNilledErrs := okReturns(example.returns)
+				callArgs := placeholders(len(example.args))
+
+				t.Run("Given().OK() sets up args and return: "+example.name, func(t *testing.T) {
+					expect := tpp.Given(example.args...).OK(returnsWithoutErrTypes...)
+					call, _ := tt.expectoriseCall(expect, callArgs)
+
+					requireEqualArgs(t, example.args, call.Arguments)
+					requireEqualArgs(t, returnsWithNilledErrs, call.ReturnArguments)
+					require.False(t, isCallOptional(call))
+				})
+
+				t.Run("Given().Maybe() sets up args and is Maybe()d: "+example.name, func(t *testing.T) {
+					expect := tpp.Given(example.args...).Maybe(example.returns...)
+					call, _ := tt.expectoriseCall(expect, callArgs)
+
+					requireEqualArgs(t, example.args, call.Arguments)
+					requireEqualArgs(t, example.returns, call.ReturnArguments)
+					require.True(t, isCallOptional(call))
+				})
+
+				t.Run("Given().Unexpected() unsets mock: "+example.name, func(t *testing.T) {
+					expect := tpp.Given(example.args...).Unexpected()
+					_, mock := tt.expectoriseCall(expect, callArgs)
+					require.Empty(t, mock.ExpectedCalls)
+				})
+
+				t.Run("Given().Times() sets args and repeatability: "+example.name, func(t *testing.T) {
+					expect := tpp.Given(example.args...).Times(3).Return(example.returns...)
+					call, _ := tt.expectoriseCall(expect, callArgs)
+
+					requireEqualArgs(t, example.args, call.Arguments)
+					require.Equal(t, 3, call.Repeatability)
+				})
+
+				t.Run("Injecting() keeps args and Times(): "+example.name, func(t *testing.T) {
+					expect := tpp.Given(example.args...).Times(3).OK()
+					for _, ret := range returnsWithoutErrTypes {
+						expect = *expect.Injecting(ret)
+					}
+					call, _ := tt.expectoriseCall(expect, callArgs)
+
+					requireEqualArgs(t, example.args, call.Arguments)
+					requireEqualArgs(t, returnsWithNilledErrs, call.ReturnArguments)
+					require.Equal(t, 3, call.Repeatability)
+				})
+
+				if contains(tt.returnTypes, "error") {
+					t.Run("Given().Err() sets up args and err return: "+example.name, func(t *testing.T) {
+						expect := tpp.Given(example.args...).Err()
+						call, _ := tt.expectoriseCall(expect, callArgs)
+
+						requireEqualArgs(t, example.args, call.Arguments)
+						require.Equal(t, expect.Err, call.ReturnArguments[errIndex(tt.returnTypes)])
+						require.False(t, isCallOptional(call))
+					})
+
+					t.Run("Given().ErrWith() sets up args and err return: "+example.name, func(t *testing.T) {
+						expect := tpp.Given(example.args...).Times(2).ErrWith(errTest)
+						call, _ := tt.expectoriseCall(expect, callArgs)
+
+						requireEqualArgs(t, example.args, call.Arguments)
+						require.Equal(t, errTest, call.ReturnArguments[errIndex(tt.returnTypes)])
+						require.Equal(t, 2, call.Repeatability)
+					})
+				}
+			}
+
 			t.Run("Unexpected() unsets mock", func(t *testing.T) {
 				expect := tpp.Unexpected()
 				_, mock := tt.expectoriseCall(expect, placeholders(len(tt.defaultArgs)))
@@ -567,6 +966,22 @@ func TestExpect(t *testing.T) {
 
 		})
 	}
+
+	t.Run("Injecting() doesn't change the original", func(t *testing.T) {
+		expect := tpp.OK(make([]any, 0, 2)...)
+		a, b := expect.Injecting(1), expect.Injecting(2)
+		require.Equal(t, []any{1}, a.Return)
+		require.Equal(t, []any{2}, b.Return)
+		require.Empty(t, expect.Return)
+	})
+
+	t.Run("Given().Times() doesn't change the builder", func(t *testing.T) {
+		given := tpp.Given(1)
+		once, twice := given.Times(1).OK(), given.Times(2).OK()
+		require.Equal(t, tpp.Given(1).OK().Once(), once)
+		require.Equal(t, tpp.Given(1).OK().Times(2), twice)
+		require.Equal(t, tpp.Given(1).OK(), given.OK())
+	})
 }
 
 // Here are a few cases for a bare testify mock call which aren't caught in the
@@ -616,6 +1031,58 @@ func TestExpectWithTestifyMock(t *testing.T) {
 	})
 }
 
+// These cover targeting a specific error return, which only makes sense for
+// mocks with more than one.
+func TestErrAt(t *testing.T) {
+	t.Run("ErrAt() sets up err return at index", func(t *testing.T) {
+		mock := testdata.NewMockMultiErrorThing(_t())
+		call := mock.EXPECT().DoThing(1)
+
+		e := tpp.ErrAt(1, errTest)
+		e.Expectorise(call)
+
+		requireEqualArgs(t, []any{0, errTest, nil}, call.ReturnArguments)
+	})
+
+	t.Run("Err() defaults to last error return", func(t *testing.T) {
+		mock := testdata.NewMockMultiErrorThing(_t())
+		call := mock.EXPECT().DoThing(1)
+
+		e := tpp.Err()
+		e.Expectorise(call)
+
+		requireEqualArgs(t, []any{0, nil, e.Err}, call.ReturnArguments)
+	})
+
+	t.Run("ErrAt() works with Injecting()", func(t *testing.T) {
+		mock := testdata.NewMockMultiErrorThing(_t())
+		call := mock.EXPECT().DoThing(1)
+
+		e := tpp.ErrAt(1, errTest)
+		e.Return = []any{42}
+		e.Expectorise(call)
+
+		requireEqualArgs(t, []any{42, errTest, nil}, call.ReturnArguments)
+	})
+
+	t.Run("ErrAt() panics for non-error index", func(t *testing.T) {
+		mock := testdata.NewMockMultiErrorThing(_t())
+		call := mock.EXPECT().DoThing(1)
+
+		e := tpp.ErrAt(0, errTest)
+		require.Panics(t, func() { e.Expectorise(call) })
+	})
+
+	t.Run("ErrAt() works with testify mock", func(t *testing.T) {
+		c := (&testifymock.Mock{}).On("Test", 1)
+
+		e := tpp.ErrAt(1, errTest)
+		e.Expectorise(c)
+
+		requireEqualArgs(t, []any{nil, errTest}, c.ReturnArguments)
+	})
+}
+
 func TestExpectMulti(t *testing.T) {
 	for _, tt := range testStructures {
 		t.Run(tt.name, func(t *testing.T) {
@@ -717,8 +1184,8 @@ func TestExpectMulti(t *testing.T) {
 
 					require.Len(t, mock.ExpectedCalls, 3)
 					for _, call := range mock.ExpectedCalls {
-						for i, ret := range tt.returnTypes {
-							if ret == "error" {
+						for i := range tt.returnTypes {
+							if i == errIndex(tt.returnTypes) {
 								_, ok := call.ReturnArguments[i].(error)
 								require.True(t, ok)
 							} else {
@@ -751,8 +1218,8 @@ func TestExpectMulti(t *testing.T) {
 
 					require.Len(t, mock.ExpectedCalls, 3)
 					for _, call := range mock.ExpectedCalls {
-						for i, ret := range tt.returnTypes {
-							if ret == "error" {
+						for i := range tt.returnTypes {
+							if i == errIndex(tt.returnTypes) {
 								rerr, ok := call.ReturnArguments[i].(error)
 								require.True(t, ok)
 								require.Equal(t, errTest, rerr)
@@ -1032,6 +1499,16 @@ func TestUnexpected(t *testing.T) {
 	})
 }
 
+// BenchmarkExpectorise measures Expectorise as a whole, for a mock call whose
+// layout is cached, as it is after the first call of its type.
+func BenchmarkExpectorise(b *testing.B) {
+	e := tpp.OK(1)
+	for i := 0; i < b.N; i++ {
+		mock := &testdata.MockIntyThing{}
+		e.Expectorise(mock.EXPECT().DoThing(1, 2))
+	}
+}
+
 var errTest = errors.New("TEST")
 
 type exampleCall struct {
@@ -1113,7 +1590,12 @@ func okReturns(returns []any) ([]any, []any) {
 	var returnsWithErroredNils []any
 	for _, r := range returns {
 		if _, ok := r.(error); ok {
-			returnsWithErroredNils = append(returnsWithErroredNils, error(nil))
+			if v := reflect.ValueOf(r); v.Kind() == reflect.Ptr && v.IsNil() {
+				// A nil concrete error is already zero valued.
+				returnsWithErroredNils = append(returnsWithErroredNils, r)
+			} else {
+				returnsWithErroredNils = append(returnsWithErroredNils, error(nil))
+			}
 			continue
 		}
 		returnsWithoutErr = append(returnsWithoutErr, r)
@@ -1122,6 +1604,17 @@ func okReturns(returns []any) ([]any, []any) {
 	return returnsWithoutErr, returnsWithErroredNils
 }
 
+// errIndex returns the index of the return type which a tpp.Err() should be
+// returned in, which is the last error.
+func errIndex(returnTypes []string) int {
+	for i := len(returnTypes) - 1; i >= 0; i-- {
+		if returnTypes[i] == "error" {
+			return i
+		}
+	}
+	return -1
+}
+
 func must(b bool, reason string) {
 	if !b {
 		panic(fmt.Sprintf("must: %s", reason))
@@ -1137,3 +1630,40 @@ func contains[S ~[]E, E comparable](s S, v E) bool {
 	}
 	return false
 }
+
+// fakeT is a require.TestingT which records failures rather than failing the
+// test, so that we can test our assertions.
+type fakeT struct {
+	failed bool
+	msgs   []string
+}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failed = true
+	f.msgs = append(f.msgs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) FailNow() {
+	f.failed = true
+}
+
+// cleanupT is a fakeT which lets us run its cleanups ourselves, for code which
+// checks things at the end of the test.
+type cleanupT struct {
+	fakeT
+	cleanups []func()
+}
+
+func (c *cleanupT) Logf(string, ...any) {}
+
+func (c *cleanupT) Cleanup(f func()) {
+	c.cleanups = append(c.cleanups, f)
+}
+
+// runCleanups runs the cleanups in reverse order, like testing.T.
+func (c *cleanupT) runCleanups() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+	c.cleanups = nil
+}
@@ -0,0 +1,173 @@
+package tpp
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The Expect type holds its returns as []any, which means that returns of the
+// wrong type are only caught at run time, when Expectorise panics. The types
+// here carry their return types statically, so the compiler will reject e.g.,
+// tpp.OK1[int]("foo").
+//
+// These are thin wrappers around Expect, so they have all the same semantics.
+// The number in the name is the number of non-error return values. Any error
+// return on the mock is handled just like it is for an Expect.
+
+// Expect1 is an Expect for mocks which return one value of type R, and
+// optionally an error.
+//
+// The zero value behaves like the zero value Expect.
+type Expect1[R any] struct {
+	expect Expect
+}
+
+// OK1 returns an Expect1 with the given return and no error.
+func OK1[R any](r R) Expect1[R] {
+	return Expect1[R]{expect: OK(r)}
+}
+
+// Err1 returns an Expect1 with a generic test error.
+func Err1[R any]() Expect1[R] {
+	return Expect1[R]{expect: errExpect(1)}
+}
+
+// ErrWith1 returns an Expect1 with the given error.
+func ErrWith1[R any](e error) Expect1[R] {
+	return Expect1[R]{expect: ErrWith(e)}
+}
+
+// Unexpected1 returns an Expect1 which is unexpected.
+func Unexpected1[R any]() Expect1[R] {
+	return Expect1[R]{expect: Unexpected()}
+}
+
+// Expect returns the equivalent untyped Expect.
+func (e Expect1[R]) Expect() Expect {
+	return e.expect
+}
+
+// Times indicates that the mock should only return the indicated number of times.
+func (e Expect1[R]) Times(n int) Expect1[R] {
+	e.expect = e.expect.Times(n)
+	return e
+}
+
+// Once indicates that the mock should only return once.
+func (e Expect1[R]) Once() Expect1[R] {
+	return e.Times(1)
+}
+
+// Expectorise configures the given mock call according to the behaviour
+// specified in the Expect1. See Expect.Expectorise.
+//
+// It panics if R isn't the mock's non-error return type.
+func (e Expect1[R]) Expectorise(mock MockCall, options ...ExpectoriseOption) *Expectation {
+	mustReturnTypes(mock, "Expect1", typeOf[R]())
+	return e.expect.Expectorise(mock, options...)
+}
+
+// Expect2 is an Expect for mocks which return two values of types R1 and R2,
+// and optionally an error.
+//
+// The zero value behaves like the zero value Expect.
+type Expect2[R1, R2 any] struct {
+	expect Expect
+}
+
+// OK2 returns an Expect2 with the given returns and no error.
+func OK2[R1, R2 any](r1 R1, r2 R2) Expect2[R1, R2] {
+	return Expect2[R1, R2]{expect: OK(r1, r2)}
+}
+
+// Err2 returns an Expect2 with a generic test error.
+func Err2[R1, R2 any]() Expect2[R1, R2] {
+	return Expect2[R1, R2]{expect: errExpect(1)}
+}
+
+// ErrWith2 returns an Expect2 with the given error.
+func ErrWith2[R1, R2 any](e error) Expect2[R1, R2] {
+	return Expect2[R1, R2]{expect: ErrWith(e)}
+}
+
+// Unexpected2 returns an Expect2 which is unexpected.
+func Unexpected2[R1, R2 any]() Expect2[R1, R2] {
+	return Expect2[R1, R2]{expect: Unexpected()}
+}
+
+// Expect returns the equivalent untyped Expect.
+func (e Expect2[R1, R2]) Expect() Expect {
+	return e.expect
+}
+
+// Times indicates that the mock should only return the indicated number of times.
+func (e Expect2[R1, R2]) Times(n int) Expect2[R1, R2] {
+	e.expect = e.expect.Times(n)
+	return e
+}
+
+// Once indicates that the mock should only return once.
+func (e Expect2[R1, R2]) Once() Expect2[R1, R2] {
+	return e.Times(1)
+}
+
+// Expectorise configures the given mock call according to the behaviour
+// specified in the Expect2. See Expect.Expectorise.
+//
+// It panics if R1 and R2 aren't the mock's non-error return types.
+func (e Expect2[R1, R2]) Expectorise(mock MockCall, options ...ExpectoriseOption) *Expectation {
+	mustReturnTypes(mock, "Expect2", typeOf[R1](), typeOf[R2]())
+	return e.expect.Expectorise(mock, options...)
+}
+
+// typedExpect is implemented by the statically typed Expect variants.
+type typedExpect interface {
+	Expect() Expect
+}
+
+// mustReturnTypes panics if the mock call's non-error returns can't be the
+// given types, in order. Bare testify calls return ...any, so there's nothing
+// to check them against, and mocks we can't make sense of are left for
+// Expectorise to complain about.
+func mustReturnTypes(mock MockCall, name string, want ...reflect.Type) {
+	layout, err := layoutOf(reflect.TypeOf(mock))
+	if err != nil || isVariadicAnyReturn(layout.returnType) {
+		return
+	}
+
+	var got []reflect.Type
+	for i := 0; i < layout.returnType.NumIn(); i++ {
+		if !layout.isErrSlot(i) {
+			got = append(got, layout.returnType.In(i))
+		}
+	}
+
+	ok := len(got) == len(want)
+	for i := 0; ok && i < len(want); i++ {
+		ok = want[i].AssignableTo(got[i])
+	}
+	if !ok {
+		panic(errors.Errorf("tpp: %s[%s] doesn't match the mock's returns (%s)", name, typeList(want), returnList(layout.returnType)))
+	}
+}
+
+// typeList returns the types as a comma separated list.
+func typeList(types []reflect.Type) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// returnList returns the args of a Return method's type as a comma separated
+// list.
+func returnList(fnType reflect.Type) string {
+	types := make([]reflect.Type, fnType.NumIn())
+	for i := range types {
+		types[i] = fnType.In(i)
+	}
+	return typeList(types)
+}
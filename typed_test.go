@@ -0,0 +1,145 @@
+package tpp_test
+
+import (
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestExpect1(t *testing.T) {
+	expectorise := func(e tpp.Expect1[int]) (*testifymock.Call, *testifymock.Mock) {
+		mock := testdata.NewMockIntyThing(_t())
+		call := mock.EXPECT().DoThing(1, 2)
+		e.Expectorise(call)
+		return call.Call, &mock.Mock
+	}
+
+	t.Run("Zero value gets empty return", func(t *testing.T) {
+		call, _ := expectorise(tpp.Expect1[int]{})
+		requireEqualArgs(t, []any{0, nil}, call.ReturnArguments)
+		require.True(t, isCallOptional(call))
+	})
+
+	t.Run("OK1() sets up return", func(t *testing.T) {
+		call, _ := expectorise(tpp.OK1(42))
+		requireEqualArgs(t, []any{42, nil}, call.ReturnArguments)
+		require.False(t, isCallOptional(call))
+	})
+
+	t.Run("Err1() sets up err return", func(t *testing.T) {
+		call, _ := expectorise(tpp.Err1[int]())
+		require.Equal(t, 0, call.ReturnArguments[0])
+		require.Error(t, call.ReturnArguments.Error(1))
+	})
+
+	t.Run("ErrWith1() sets up err return", func(t *testing.T) {
+		call, _ := expectorise(tpp.ErrWith1[int](errTest))
+		requireEqualArgs(t, []any{0, errTest}, call.ReturnArguments)
+	})
+
+	t.Run("Unexpected1() unsets mock", func(t *testing.T) {
+		_, mock := expectorise(tpp.Unexpected1[int]())
+		require.Empty(t, mock.ExpectedCalls)
+	})
+
+	t.Run("Times() sets repeatability", func(t *testing.T) {
+		call, _ := expectorise(tpp.OK1(42).Times(3))
+		require.Equal(t, 3, call.Repeatability)
+	})
+
+	t.Run("Expect() converts to Expect", func(t *testing.T) {
+		require.Equal(t, tpp.OK(42), tpp.OK1(42).Expect())
+	})
+
+	t.Run("NameCase() names typed Expects", func(t *testing.T) {
+		tc := struct {
+			getFoo tpp.Expect1[int]
+			getBar tpp.Expect1[string]
+		}{
+			getFoo: tpp.Err1[int](),
+		}
+		require.Equal(t, "getFoo=Err", tpp.NameCase(tc))
+	})
+}
+
+func TestExpect2(t *testing.T) {
+	expectorise := func(e tpp.Expect2[int, string]) *testifymock.Call {
+		call := (&testifymock.Mock{}).On("Test", 1)
+		e.Expectorise(call)
+		return call
+	}
+
+	t.Run("OK2() sets up return", func(t *testing.T) {
+		call := expectorise(tpp.OK2(42, "foo"))
+		requireEqualArgs(t, []any{42, "foo"}, call.ReturnArguments)
+	})
+
+	t.Run("Err2() sets up err return", func(t *testing.T) {
+		call := expectorise(tpp.Err2[int, string]())
+		require.Error(t, call.ReturnArguments.Error(0))
+	})
+
+	t.Run("Unexpected2() unsets mock", func(t *testing.T) {
+		mock := &testifymock.Mock{}
+		e := tpp.Unexpected2[int, string]()
+		e.Expectorise(mock.On("Test", 1))
+		require.Empty(t, mock.ExpectedCalls)
+	})
+
+	t.Run("Once() sets repeatability", func(t *testing.T) {
+		call := expectorise(tpp.OK2(42, "foo").Once())
+		require.Equal(t, 1, call.Repeatability)
+	})
+
+	t.Run("mockery mock", func(t *testing.T) {
+		expectorise := func(e tpp.Expect2[int, string]) *testifymock.Call {
+			call := testdata.NewMockPairyThing(_t()).EXPECT().DoThing(1)
+			e.Expectorise(call)
+			return call.Call
+		}
+
+		t.Run("OK2() sets up return", func(t *testing.T) {
+			call := expectorise(tpp.OK2(42, "foo"))
+			requireEqualArgs(t, []any{42, "foo", nil}, call.ReturnArguments)
+		})
+
+		t.Run("Err2() puts err in the error return", func(t *testing.T) {
+			call := expectorise(tpp.Err2[int, string]())
+			require.Equal(t, 0, call.ReturnArguments[0])
+			require.Equal(t, "", call.ReturnArguments[1])
+			require.Error(t, call.ReturnArguments.Error(2))
+		})
+
+		t.Run("ErrWith2() puts err in the error return", func(t *testing.T) {
+			call := expectorise(tpp.ErrWith2[int, string](errTest))
+			requireEqualArgs(t, []any{0, "", errTest}, call.ReturnArguments)
+		})
+	})
+}
+
+func TestTypedReturnTypes(t *testing.T) {
+	t.Run("Expect1 of wrong type panics", func(t *testing.T) {
+		call := testdata.NewMockIntyThing(_t()).EXPECT().DoThing(1, 2)
+		require.PanicsWithError(t, "tpp: Expect1[string] doesn't match the mock's returns (int, error)", func() {
+			tpp.OK1("foo").Expectorise(call)
+		})
+	})
+
+	t.Run("Expect2 of wrong types panics", func(t *testing.T) {
+		call := testdata.NewMockPairyThing(_t()).EXPECT().DoThing(1)
+		require.PanicsWithError(t, "tpp: Expect2[string, int] doesn't match the mock's returns (int, string, error)", func() {
+			tpp.OK2("foo", 42).Expectorise(call)
+		})
+	})
+
+	t.Run("Expect2 for one return panics", func(t *testing.T) {
+		call := testdata.NewMockIntyThing(_t()).EXPECT().DoThing(1, 2)
+		require.PanicsWithError(t, "tpp: Expect2[int, string] doesn't match the mock's returns (int, error)", func() {
+			tpp.OK2(42, "foo").Expectorise(call)
+		})
+	})
+}
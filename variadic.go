@@ -0,0 +1,180 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// Mockery generates mocks for variadic methods, such as
+//
+//	DoThing(ctx context.Context, keys ...string) error
+//
+// in one of two ways, depending on its unroll-variadic setting. By default,
+// each of the variadic args is passed to testify as a separate arg. With
+// unroll-variadic: false, they're passed as a single []string. Either way, the
+// variadic args can be given to tpp.Given() just like any others:
+//
+//	getFoo: tpp.Given(ctx, "a", "b").Return(nil)
+//
+// and Expectorised with a single tpp.Arg() in place of the variadic args:
+//
+//	tt.getFoo.Expectorise(mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+//
+// For packed mocks, pass WithPackedVariadic() to Expectorise as well. Mock calls
+// which are always packed can say so by implementing PackedVariadic(), as
+// sqlstub's do, so that it isn't needed.
+//
+// Note that Expects without tpp.Given(), such as tpp.Err(), replace the tpp.Arg()
+// with mock.Anything, just as they do for any other args. For unrolled mocks,
+// that only matches a single variadic arg.
+
+// Rest matches all of the variadic args of a mocked method. It must be the last
+// arg given to tpp.Given(), in place of the variadic args. For example:
+//
+//	tpp.Given(ctx, tpp.Rest(mock.Anything)).Return(nil)
+//
+// Mocks generated with mockery's default unroll-variadic: true match each
+// variadic arg separately, so the number of them has to be known up front. For
+// these, Rest can only take a slice of args (or matchers), such as
+// tpp.Rest([]string{"a", "b"}). To match any number of variadic args, generate
+// the mock with unroll-variadic: false and use WithPackedVariadic().
+func Rest(matcher any) restArg {
+	return restArg{matcher: matcher}
+}
+
+type restArg struct {
+	matcher any
+}
+
+// packedVariadicCall is implemented by mock calls which always pass their
+// variadic args to testify as a single slice.
+type packedVariadicCall interface {
+	PackedVariadic()
+}
+
+func isPackedVariadic(mock MockCall) bool {
+	_, ok := mock.(packedVariadicCall)
+	return ok
+}
+
+// variadicTail returns the args which should be passed to testify for the given
+// variadic args, whose element type is elem.
+func variadicTail(tail []any, elem reflect.Type, packed bool) []any {
+	for i, a := range tail {
+		r, ok := a.(restArg)
+		if !ok {
+			continue
+		}
+		if i != 0 || len(tail) != 1 {
+			panic("tpp.Rest() must be given in place of all of the variadic args")
+		}
+		return restTail(r.matcher, elem, packed)
+	}
+
+	if !packed {
+		return tail
+	}
+
+	// A single arg which can't be one of the variadic args is taken to be all of
+	// them, e.g., a []string or a matcher. This is what you'd pass to the mock
+	// without tpp.
+	if len(tail) == 1 && isPackedArg(tail[0], elem) {
+		return tail
+	}
+
+	return []any{packTail(tail, elem)}
+}
+
+// restTail returns the args which should be passed to testify for tpp.Rest().
+func restTail(matcher any, elem reflect.Type, packed bool) []any {
+	v := reflect.ValueOf(matcher)
+	isSlice := matcher != nil && v.Kind() == reflect.Slice
+
+	switch {
+	case packed && isSlice && v.Type() != reflect.SliceOf(elem):
+		// E.g., a []any with some matchers in it.
+		return []any{packTail(sliceToArgs(v), elem)}
+	case packed:
+		return []any{matcher}
+	case isSlice:
+		return sliceToArgs(v)
+	default:
+		panic(fmt.Sprintf(
+			"\ntpp.Rest() can't match the variadic args with %T!\n"+
+				"\n"+
+				"The mock passes each variadic arg to testify separately, so tpp.Rest() needs\n"+
+				"a slice with one arg (or matcher) for each of them. To match any number of\n"+
+				"variadic args, generate the mock with unroll-variadic: false and use\n"+
+				"tpp.WithPackedVariadic().\n",
+			matcher,
+		))
+	}
+}
+
+// packTail returns an arg which matches a packed slice of variadic args against
+// tail. If the tail is all plain values, that's just the slice itself. If not,
+// it's a mock.MatchedBy which matches each element, like testify would.
+func packTail(tail []any, elem reflect.Type) any {
+	sliceType := reflect.SliceOf(elem)
+
+	if len(tail) > 0 && allAssignable(tail, elem) {
+		packed := reflect.MakeSlice(sliceType, len(tail), len(tail))
+		for i, a := range tail {
+			packed.Index(i).Set(reflect.ValueOf(a))
+		}
+		return packed.Interface()
+	}
+
+	// mock.MatchedBy needs a func which takes the type being matched, so we
+	// need to make one.
+	want := testifymock.Arguments(tail)
+	fnType := reflect.FuncOf(
+		[]reflect.Type{sliceType},
+		[]reflect.Type{reflect.TypeOf(false)},
+		false,
+	)
+	fn := reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+		_, diffs := want.Diff(sliceToArgs(in[0]))
+		return []reflect.Value{reflect.ValueOf(diffs == 0)}
+	})
+
+	return testifymock.MatchedBy(fn.Interface())
+}
+
+// allAssignable returns whether all of args are plain values of type elem,
+// i.e., not nil and not matchers. We can't tell the difference for interface
+// types, so we don't try.
+func allAssignable(args []any, elem reflect.Type) bool {
+	if elem.Kind() == reflect.Interface {
+		return false
+	}
+	for _, a := range args {
+		if a == nil || a == testifymock.Anything || !reflect.TypeOf(a).AssignableTo(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPackedArg returns whether arg stands for all of the variadic args, rather
+// than being one of them.
+func isPackedArg(arg any, elem reflect.Type) bool {
+	if arg == testifymock.Anything {
+		return true
+	}
+	if arg == nil {
+		return false
+	}
+	t := reflect.TypeOf(arg)
+	return t == reflect.SliceOf(elem) || !t.AssignableTo(elem)
+}
+
+func sliceToArgs(v reflect.Value) []any {
+	args := make([]any, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+	return args
+}
@@ -0,0 +1,176 @@
+package tpp_test
+
+import (
+	"context"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestVariadic(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name     string
+		expect   tpp.Expect
+		call     []string
+		wantArgs []any
+	}{
+		{
+			name:     "OK: Given() args",
+			expect:   tpp.Given(ctx, "a", "b").Return(nil),
+			call:     []string{"a", "b"},
+			wantArgs: []any{ctx, "a", "b"},
+		},
+		{
+			name:     "OK: Given() no variadic args",
+			expect:   tpp.Given(ctx).Return(nil),
+			call:     nil,
+			wantArgs: []any{ctx},
+		},
+		{
+			name:     "OK: Given() matcher",
+			expect:   tpp.Given(ctx, "a", testifymock.Anything).Return(nil),
+			call:     []string{"a", "b"},
+			wantArgs: []any{ctx, "a", testifymock.Anything},
+		},
+		{
+			name:     "OK: Rest() slice",
+			expect:   tpp.Given(ctx, tpp.Rest([]string{"a", "b"})).Return(nil),
+			call:     []string{"a", "b"},
+			wantArgs: []any{ctx, "a", "b"},
+		},
+		{
+			name:     "OK: Rest() slice of matchers",
+			expect:   tpp.Given(ctx, tpp.Rest([]any{"a", testifymock.Anything})).Return(nil),
+			call:     []string{"a", "b"},
+			wantArgs: []any{ctx, "a", testifymock.Anything},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testdata.NewMockVariadicThing(t)
+			call := mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg())
+			tt.expect.Expectorise(call)
+
+			requireEqualArgs(t, tt.wantArgs, call.Arguments)
+			require.NoError(t, mock.DoThing(ctx, tt.call...))
+		})
+	}
+
+	t.Run("Rest() panics for matcher", func(t *testing.T) {
+		mock := testdata.NewMockVariadicThing(_t())
+		call := mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg())
+
+		e := tpp.Given(ctx, tpp.Rest(testifymock.Anything)).Return(nil)
+		require.Panics(t, func() { e.Expectorise(call) })
+	})
+
+	t.Run("Rest() panics if not last", func(t *testing.T) {
+		mock := testdata.NewMockVariadicThing(_t())
+		call := mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg())
+
+		e := tpp.Given(ctx, tpp.Rest([]string{"a"}), "b").Return(nil)
+		require.Panics(t, func() { e.Expectorise(call) })
+	})
+
+	t.Run("Arg() for each variadic arg", func(t *testing.T) {
+		mock := testdata.NewMockVariadicThing(t)
+		call := mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg(), tpp.Arg())
+		e := tpp.Given(ctx, "a", "b").Return(nil)
+		e.Expectorise(call)
+
+		requireEqualArgs(t, []any{ctx, "a", "b"}, call.Arguments)
+		require.NoError(t, mock.DoThing(ctx, "a", "b"))
+	})
+
+	t.Run("Err() matches one variadic arg", func(t *testing.T) {
+		// Without Given(), the tpp.Arg() is just mock.Anything, which testify
+		// matches against one of the unrolled variadic args.
+		mock := testdata.NewMockVariadicThing(t)
+		e := tpp.Err()
+		e.Expectorise(mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg()))
+
+		require.Equal(t, e.Err, mock.DoThing(ctx, "a"))
+	})
+}
+
+func TestPackedVariadic(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Err() matches any variadic args", func(t *testing.T) {
+		mock := testdata.NewMockPackedVariadicThing(t)
+		e := tpp.Err()
+		e.Expectorise(mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg()), tpp.WithPackedVariadic())
+
+		require.Equal(t, e.Err, mock.DoThing(ctx, "a", "b"))
+	})
+
+	for _, tt := range []struct {
+		name  string
+		given []any
+		call  []string
+		miss  []string
+	}{
+		{
+			name:  "OK: Given() args",
+			given: []any{ctx, "a", "b"},
+			call:  []string{"a", "b"},
+			miss:  []string{"a"},
+		},
+		{
+			name:  "OK: Given() no variadic args",
+			given: []any{ctx},
+			call:  nil,
+			miss:  []string{"a"},
+		},
+		{
+			name:  "OK: Given() matcher",
+			given: []any{ctx, "a", testifymock.Anything},
+			call:  []string{"a", "b"},
+			miss:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "OK: Given() slice",
+			given: []any{ctx, []string{"a", "b"}},
+			call:  []string{"a", "b"},
+			miss:  []string{"b", "a"},
+		},
+		{
+			name: "OK: Given() slice matcher",
+			given: []any{ctx, testifymock.MatchedBy(func(keys []string) bool {
+				return len(keys) == 2
+			})},
+			call: []string{"a", "b"},
+			miss: []string{"a"},
+		},
+		{
+			name:  "OK: Rest() matcher",
+			given: []any{ctx, tpp.Rest(testifymock.Anything)},
+			call:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "OK: Rest() slice of matchers",
+			given: []any{ctx, tpp.Rest([]any{"a", testifymock.Anything})},
+			call:  []string{"a", "b"},
+			miss:  []string{"b", "b"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testdata.NewMockPackedVariadicThing(t)
+			call := mock.EXPECT().DoThing(tpp.Arg(), tpp.Arg())
+			e := tpp.Given(tt.given...).Return(nil)
+			e.Expectorise(call, tpp.WithPackedVariadic())
+
+			require.NoError(t, mock.DoThing(ctx, tt.call...))
+
+			if tt.miss != nil {
+				_, diffs := call.Arguments.Diff([]any{ctx, tt.miss})
+				require.NotZero(t, diffs)
+			}
+		})
+	}
+}
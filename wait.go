@@ -0,0 +1,90 @@
+package tpp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// waitForPoll is how often WaitFor checks whether the calls have been made.
+const waitForPoll = time.Millisecond
+
+// WaitFor blocks until all of the given mock calls, as configured by
+// Expectorise, have been made, or the timeout is hit. It returns whether they
+// were all made.
+//
+// This is useful for subjects which call their dependencies from goroutines,
+// and so may return before they do. Without it, AssertExpectations in the
+// mock's cleanup would race with the subject. For example:
+//
+//	getFoo := tt.getFoo.Expectorise(mock.EXPECT().GetFoo())
+//	saveFoos := tpp.ExpectoriseMulti(tt.saveFoos, func() tpp.MockCall {
+//		return mock.EXPECT().SaveFoo(tpp.Arg())
+//	})
+//
+//	subject.XXX()
+//
+//	tpp.WaitFor(t, time.Second, append(saveFoos, getFoo)...)
+//
+// Only calls which must be made are waited for, i.e., those whose Expect has
+// Expected set to true, and they're waited for until they've been made the
+// number of times given by Times, or the minimum given by AtLeast or Between.
+// Only the calls routed to each one count, not those with matching args which
+// testify routes to another call. They're counted by a Run hook, so any Run
+// func must be set on the call before Expectorise, not after.
+//
+// If the timeout is hit, the test fails with a list of the outstanding calls.
+func WaitFor(t require.TestingT, timeout time.Duration, expectations ...*Expectation) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	var waiting []*Expectation
+	for i, x := range expectations {
+		if x == nil {
+			t.Errorf("tpp: WaitFor given nil Expectation %d", i)
+			return false
+		}
+		if x.expect.Expected == nil || !*x.expect.Expected {
+			continue
+		}
+		if x.call == nil {
+			t.Errorf("tpp: WaitFor given Expectation %d (%s) without a *mock.Call to wait for", i, x.expect.describe())
+			return false
+		}
+		waiting = append(waiting, x)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		outstanding := outstandingCalls(waiting)
+		if len(outstanding) == 0 {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			t.Errorf(
+				"tpp: timed out after %s waiting for calls:\n%s",
+				timeout,
+				strings.Join(outstanding, "\n"),
+			)
+			return false
+		}
+
+		time.Sleep(waitForPoll)
+	}
+}
+
+// outstandingCalls returns descriptions of the Expectations' calls which
+// haven't been made as many times as they need to be.
+func outstandingCalls(expectations []*Expectation) []string {
+	var outstanding []string
+	for _, x := range expectations {
+		if made, ok := x.callsMade(); !ok {
+			outstanding = append(outstanding, fmt.Sprintf("\t%s%v (%s)", x.call.Method, x.call.Arguments, made))
+		}
+	}
+	return outstanding
+}
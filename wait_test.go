@@ -0,0 +1,126 @@
+package tpp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestWaitFor(t *testing.T) {
+	t.Run("waits for async call", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		e := tpp.OK(1)
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2))
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_, _ = mock.DoThing(1, 2)
+		}()
+
+		require.True(t, tpp.WaitFor(t, time.Second, x))
+	})
+
+	t.Run("waits for Times()", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		e := tpp.OK(1).Times(3)
+		x := e.Expectorise(mock.EXPECT().DoThing(1, 2))
+
+		go func() {
+			for i := 0; i < 3; i++ {
+				time.Sleep(time.Millisecond)
+				_, _ = mock.DoThing(1, 2)
+			}
+		}()
+
+		require.True(t, tpp.WaitFor(t, time.Second, x))
+	})
+
+	t.Run("waits for ExpectoriseMulti()", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		ee := []tpp.Expect{tpp.OK(1).Once(), tpp.OK(2).Once()}
+		xs := tpp.ExpectoriseMulti(ee, func() tpp.MockCall {
+			return mock.EXPECT().DoThing(1, 2)
+		})
+
+		go func() {
+			_, _ = mock.DoThing(1, 2)
+			_, _ = mock.DoThing(1, 2)
+		}()
+
+		require.True(t, tpp.WaitFor(t, time.Second, xs...))
+	})
+
+	t.Run("doesn't wait for Maybe or Unexpected", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(t)
+		var maybe tpp.Expect
+		unexpected := tpp.Unexpected()
+
+		require.True(t, tpp.WaitFor(t, time.Second,
+			maybe.Expectorise(mock.EXPECT().DoThing(1, 2)),
+			unexpected.Expectorise(mock.EXPECT().DoThing(3, 4)),
+		))
+	})
+
+	t.Run("reports outstanding calls on timeout", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(_t())
+		called, notCalled := tpp.OK(1), tpp.OK(2).Times(2)
+		xs := []*tpp.Expectation{
+			called.Expectorise(mock.EXPECT().DoThing(1, 2)),
+			notCalled.Expectorise(mock.EXPECT().DoThing(3, 4)),
+		}
+		_, _ = mock.DoThing(1, 2)
+
+		ft := &fakeT{}
+		require.False(t, tpp.WaitFor(ft, 10*time.Millisecond, xs...))
+		require.True(t, ft.failed)
+		require.Len(t, ft.msgs, 1)
+		require.Contains(t, ft.msgs[0], "DoThing[3 4] (2 more time(s) needed)")
+		require.NotContains(t, ft.msgs[0], "DoThing[1 2]")
+	})
+
+	t.Run("only counts calls routed to the call", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(_t())
+		first, second := tpp.OK(1).Once(), tpp.Err().Once()
+		x1 := first.Expectorise(mock.EXPECT().DoThing(1, 2))
+		x2 := second.Expectorise(mock.EXPECT().DoThing(1, 2))
+		_, _ = mock.DoThing(1, 2)
+
+		require.True(t, tpp.WaitFor(t, time.Second, x1))
+
+		ft := &fakeT{}
+		require.False(t, tpp.WaitFor(ft, 10*time.Millisecond, x2))
+		require.Contains(t, ft.msgs[0], "DoThing[1 2] (1 more time(s) needed)")
+	})
+
+	t.Run("waits for each Expectorise of a shared Expect", func(t *testing.T) {
+		e := tpp.OK(1)
+		mock1, mock2 := testdata.NewMockIntyThing(_t()), testdata.NewMockIntyThing(_t())
+		x1 := e.Expectorise(mock1.EXPECT().DoThing(1, 2))
+		x2 := e.Expectorise(mock2.EXPECT().DoThing(1, 2))
+		_, _ = mock2.DoThing(1, 2)
+
+		require.True(t, tpp.WaitFor(t, time.Second, x2))
+		require.False(t, tpp.WaitFor(&fakeT{}, 10*time.Millisecond, x1))
+	})
+
+	t.Run("ExpectoriseMulti() leaves the Expects alone", func(t *testing.T) {
+		mock := testdata.NewMockIntyThing(_t())
+		ee := []tpp.Expect{tpp.OK(1).Once(), tpp.OK(2).Once()}
+		want := append([]tpp.Expect{}, ee...)
+		tpp.ExpectoriseMulti(ee, func() tpp.MockCall {
+			return mock.EXPECT().DoThing(1, 2)
+		})
+
+		require.Equal(t, want, ee)
+	})
+
+	t.Run("fails on nil Expectation", func(t *testing.T) {
+		ft := &fakeT{}
+		require.False(t, tpp.WaitFor(ft, time.Second, nil))
+		require.True(t, ft.failed)
+	})
+}
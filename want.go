@@ -0,0 +1,130 @@
+package tpp
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// Want is what a test case expects of the subject's output: a result of type T
+// and an error. It's the counterpart of Expect, for the other side of a table.
+// For example:
+//
+//	for _, tt := range []struct {
+//		name   string
+//		getFoo tpp.Expect
+//		want   tpp.Want[*Foo]
+//	}{
+//		{
+//			name:   "OK",
+//			getFoo: tpp.OK(&Foo{}),
+//			want:   tpp.WantValue(&Foo{}),
+//		},
+//		{
+//			name:   "ERR: not found",
+//			getFoo: tpp.ErrWith(ErrNotFound),
+//			want:   tpp.WantErrIs[*Foo](ErrNotFound),
+//		},
+//	} {
+//		t.Run(tt.name, func(t *testing.T) {
+//			...
+//			got, err := subject.XXX()
+//			tt.want.Check(t, got, err)
+//		})
+//	}
+//
+// Go can't infer T for the constructors which aren't given a T, so it has to
+// be given explicitly, as above.
+//
+// The zero Want is the same as WantAny.
+type Want[T any] struct {
+	desc  string
+	check func(t require.TestingT, got T, err error)
+}
+
+// WantValue returns a Want for no error and a result equal to v, as compared
+// by require.Equal.
+func WantValue[T any](v T) Want[T] {
+	return Want[T]{
+		desc: fmt.Sprintf("WantValue(%v)", v),
+		check: func(t require.TestingT, got T, err error) {
+			require.NoError(t, err)
+			require.Equal(t, v, got)
+		},
+	}
+}
+
+// WantMatch returns a Want for no error and a result for which match returns
+// true.
+func WantMatch[T any](match func(T) bool) Want[T] {
+	return Want[T]{
+		desc: "WantMatch",
+		check: func(t require.TestingT, got T, err error) {
+			require.NoError(t, err)
+			if !match(got) {
+				t.Errorf("tpp: got %v, which doesn't match", got)
+				t.FailNow()
+			}
+		},
+	}
+}
+
+// WantErrIs returns a Want for an error which is, or wraps, target, as checked
+// by errors.Is. The result isn't checked. It panics if target is nil.
+func WantErrIs[T any](target error) Want[T] {
+	if target == nil {
+		panic("tpp: WantErrIs given a nil error; use WantValue or WantAny instead")
+	}
+
+	return Want[T]{
+		desc: fmt.Sprintf("WantErrIs(%v)", target),
+		check: func(t require.TestingT, _ T, err error) {
+			require.ErrorIs(t, err, target)
+		},
+	}
+}
+
+// WantErrAs returns a Want for an error which is, or wraps, an error of type E,
+// as checked by errors.As. The result isn't checked.
+func WantErrAs[T any, E error]() Want[T] {
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+
+	return Want[T]{
+		desc: fmt.Sprintf("WantErrAs[%s]", typ),
+		check: func(t require.TestingT, _ T, err error) {
+			var target E
+			if !errors.As(err, &target) {
+				t.Errorf("tpp: expected an error of type %s in the chain, but got: %v", typ, err)
+				t.FailNow()
+			}
+		},
+	}
+}
+
+// WantAny returns a Want which accepts any result and error. This is useful
+// for cases which only care about how the subject calls its dependencies.
+func WantAny[T any]() Want[T] {
+	return Want[T]{}
+}
+
+// Check asserts that the subject's output is as wanted, failing the test if
+// not.
+func (w Want[T]) Check(t require.TestingT, got T, err error) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if w.check != nil {
+		w.check(t, got, err)
+	}
+}
+
+// String describes the Want, in terms of the function used to construct it.
+func (w Want[T]) String() string {
+	if w.check == nil {
+		return "WantAny"
+	}
+	return w.desc
+}
@@ -0,0 +1,122 @@
+package tpp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattavos/tpp"
+	"github.com/mattavos/tpp/testdata"
+)
+
+func TestWant(t *testing.T) {
+	errWrapped := fmt.Errorf("getting foo: %w", errTest)
+	errStatus := fmt.Errorf("getting foo: %w", &testdata.StatusError{Status: 404})
+
+	for _, tt := range []struct {
+		name       string
+		want       tpp.Want[*testdata.Struct]
+		got        *testdata.Struct
+		err        error
+		wantFailed bool
+	}{
+		{
+			name: "OK: WantValue",
+			want: tpp.WantValue(&testdata.Struct{A: 1}),
+			got:  &testdata.Struct{A: 1},
+		},
+		{
+			name:       "ERR: WantValue: different value",
+			want:       tpp.WantValue(&testdata.Struct{A: 1}),
+			got:        &testdata.Struct{A: 2},
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: WantValue: error",
+			want:       tpp.WantValue(&testdata.Struct{A: 1}),
+			got:        &testdata.Struct{A: 1},
+			err:        errTest,
+			wantFailed: true,
+		},
+		{
+			name: "OK: WantMatch",
+			want: tpp.WantMatch(func(s *testdata.Struct) bool { return s.A > 0 }),
+			got:  &testdata.Struct{A: 1},
+		},
+		{
+			name:       "ERR: WantMatch: no match",
+			want:       tpp.WantMatch(func(s *testdata.Struct) bool { return s.A > 0 }),
+			got:        &testdata.Struct{},
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: WantMatch: error",
+			want:       tpp.WantMatch(func(s *testdata.Struct) bool { return true }),
+			err:        errTest,
+			wantFailed: true,
+		},
+		{
+			name: "OK: WantErrIs",
+			want: tpp.WantErrIs[*testdata.Struct](errTest),
+			err:  errWrapped,
+		},
+		{
+			name:       "ERR: WantErrIs: other error",
+			want:       tpp.WantErrIs[*testdata.Struct](errTest),
+			err:        errStatus,
+			wantFailed: true,
+		},
+		{
+			name:       "ERR: WantErrIs: no error",
+			want:       tpp.WantErrIs[*testdata.Struct](errTest),
+			wantFailed: true,
+		},
+		{
+			name: "OK: WantErrAs",
+			want: tpp.WantErrAs[*testdata.Struct, *testdata.StatusError](),
+			err:  errStatus,
+		},
+		{
+			name: "OK: WantErrAs interface",
+			want: tpp.WantErrAs[*testdata.Struct, testdata.CodedError](),
+			err:  errStatus,
+		},
+		{
+			name:       "ERR: WantErrAs: other error",
+			want:       tpp.WantErrAs[*testdata.Struct, *testdata.StatusError](),
+			err:        errWrapped,
+			wantFailed: true,
+		},
+		{
+			name: "OK: WantAny: value",
+			want: tpp.WantAny[*testdata.Struct](),
+			got:  &testdata.Struct{},
+		},
+		{
+			name: "OK: WantAny: error",
+			want: tpp.WantAny[*testdata.Struct](),
+			err:  errTest,
+		},
+		{
+			name: "OK: zero Want",
+			err:  errTest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			tt.want.Check(ft, tt.got, tt.err)
+			require.Equal(t, tt.wantFailed, ft.failed, ft.msgs)
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		require.Equal(t, "WantValue(1)", tpp.WantValue(1).String())
+		require.Equal(t, "WantErrAs[testdata.CodedError]", tpp.WantErrAs[int, testdata.CodedError]().String())
+		require.Equal(t, "WantAny", tpp.Want[int]{}.String())
+	})
+
+	t.Run("WantErrIs panics on nil", func(t *testing.T) {
+		require.Panics(t, func() { tpp.WantErrIs[int](nil) })
+	})
+}